@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	"github.com/conductorone/baton-sdk/pkg/logging"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+)
+
+// getConfigPath and loadConfig mirror the unexported config loading of baton-sdk v0.1.5 pkg/cli,
+// so subcommands read the config file, environment and flags exactly like the connector command. Unlike the SDK,
+// loadConfig reports malformed config files instead of ignoring them. Keep them in sync with the SDK when it is upgraded.
+func getConfigPath(customPath string) (string, string, error) {
+	if customPath != "" {
+		cfgDir, cfgFile := filepath.Split(filepath.Clean(customPath))
+		if cfgDir == "" {
+			cfgDir = "."
+		}
+
+		ext := filepath.Ext(cfgFile)
+		if ext == "" || (ext != ".yaml" && ext != ".yml") {
+			return "", "", errors.New("expected config file to have .yaml or .yml extension")
+		}
+
+		return strings.TrimSuffix(cfgDir, string(filepath.Separator)), strings.TrimSuffix(cfgFile, ext), nil
+	}
+
+	return ".", ".baton", nil
+}
+
+func loadConfig(cmd *cobra.Command) (*config, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	cfgPath, cfgName, err := getConfigPath(os.Getenv("BATON_CONFIG_PATH"))
+	if err != nil {
+		return nil, err
+	}
+
+	v.SetConfigName(cfgName)
+	v.AddConfigPath(cfgPath)
+
+	// a missing config file is fine, a malformed one is reported.
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			return nil, err
+		}
+	}
+
+	v.SetEnvPrefix("baton")
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+	if err := v.BindPFlags(cmd.PersistentFlags()); err != nil {
+		return nil, err
+	}
+	if err := v.BindPFlags(cmd.Flags()); err != nil {
+		return nil, err
+	}
+
+	cfg := &config{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// setupSubcommand loads and validates the configuration, sets up logging and returns an authenticated Box client.
+func setupSubcommand(ctx context.Context, cmd *cobra.Command) (context.Context, *box.Client, *config, error) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ctx, err = logging.Init(ctx, logging.WithLogFormat(cfg.LogFormat), logging.WithLogLevel(cfg.LogLevel))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := validateConfig(ctx, cfg); err != nil {
+		return nil, nil, nil, err
+	}
+
+	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// subcommands such as events --follow outlive a single access token, so tokens are renewed as they expire.
+	tokenSource, err := box.NewTokenSource(ctx, cfg.ClientID, cfg.ClientSecret, cfg.EnterpriseID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if _, err := tokenSource.Token(); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	httpClient.Transport = &oauth2.Transport{Source: tokenSource, Base: httpClient.Transport}
+
+	return ctx, box.NewClient(httpClient, "", cfg.UserType, cfg.UserFields), cfg, nil
+}
+
+// userResolver is the part of the Box client needed to resolve a user by login or ID.
+type userResolver interface {
+	GetUser(ctx context.Context, userId string) (box.User, error)
+	GetUserByLogin(ctx context.Context, login string) (box.User, error)
+}
+
+// resolveUser returns the Box user with the given ID or login.
+func resolveUser(ctx context.Context, client userResolver, key string) (box.User, error) {
+	if _, err := strconv.ParseUint(key, 10, 64); err == nil {
+		return client.GetUser(ctx, key)
+	}
+	return client.GetUserByLogin(ctx, key)
+}
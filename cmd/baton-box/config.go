@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/conductorone/baton-box/pkg/box"
 	"github.com/conductorone/baton-sdk/pkg/cli"
 	"github.com/spf13/cobra"
 )
@@ -12,9 +14,13 @@ import (
 type config struct {
 	cli.BaseConfig `mapstructure:",squash"` // Puts the base config options in the same place as the connector options
 
-	ClientID     string `mapstructure:"box-client-id"`
-	ClientSecret string `mapstructure:"box-client-secret"`
-	EnterpriseID string `mapstructure:"enterprise-id"`
+	ClientID                  string        `mapstructure:"box-client-id"`
+	ClientSecret              string        `mapstructure:"box-client-secret"`
+	EnterpriseID              string        `mapstructure:"enterprise-id"`
+	UserType                  string        `mapstructure:"user-type"`
+	UserFields                []string      `mapstructure:"user-fields"`
+	ActivityLookback          time.Duration `mapstructure:"activity-lookback"`
+	AllowExternalGroupChanges bool          `mapstructure:"allow-external-group-changes"`
 }
 
 // validateConfig is run after the configuration is loaded, and should return an error if it isn't valid.
@@ -28,6 +34,17 @@ func validateConfig(ctx context.Context, cfg *config) error {
 	if cfg.EnterpriseID == "" {
 		return fmt.Errorf("enterprise id is missing")
 	}
+	switch cfg.UserType {
+	case box.UserTypeAll, box.UserTypeManaged:
+	case box.UserTypeExternal:
+		// Box only returns external users for a filter term, so listing them all yields no users.
+		return fmt.Errorf("user type %s is not supported, the Box API only lists external users matching a search term", box.UserTypeExternal)
+	default:
+		return fmt.Errorf("user type must be one of %s or %s", box.UserTypeAll, box.UserTypeManaged)
+	}
+	if cfg.ActivityLookback < 0 {
+		return fmt.Errorf("activity lookback must not be negative")
+	}
 	return nil
 }
 
@@ -36,4 +53,16 @@ func cmdFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().String("box-client-id", "", "Client ID used to authenticate to the Box API. ($BATON_BOX_CLIENT_ID)")
 	cmd.PersistentFlags().String("box-client-secret", "", "Client Secret used to authenticate to the Box API. ($BATON_BOX_CLIENT_SECRET)")
 	cmd.PersistentFlags().String("enterprise-id", "", "ID of your Box enterprise. ($BATON_ENTERPRISE_ID)")
+	cmd.PersistentFlags().String("user-type", box.UserTypeAll, "Kind of Box users to sync: all or managed. ($BATON_USER_TYPE)")
+	cmd.PersistentFlags().StringSlice("user-fields", nil, "Additional Box user fields to add to the user profile. ($BATON_USER_FIELDS)")
+	cmd.PersistentFlags().Duration(
+		"activity-lookback",
+		0,
+		"How far back to read admin events for last login and last activity of users, e.g. 720h. Disabled when 0. ($BATON_ACTIVITY_LOOKBACK)",
+	)
+	cmd.PersistentFlags().Bool(
+		"allow-external-group-changes",
+		false,
+		"Allow provisioning to change members of groups synced from an external identity provider. ($BATON_ALLOW_EXTERNAL_GROUP_CHANGES)",
+	)
 }
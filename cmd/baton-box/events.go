@@ -0,0 +1,408 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	eventsFormatJSONL = "jsonl"
+	eventsFormatCSV   = "csv"
+)
+
+var eventsCSVHeader = []string{
+	"event_id",
+	"event_type",
+	"created_at",
+	"created_by_id",
+	"created_by_login",
+	"source_type",
+	"source_id",
+	"ip_address",
+	"additional_details",
+}
+
+// eventsCheckpoint is the stream position persisted after every written chunk, so an export can be resumed.
+type eventsCheckpoint struct {
+	StreamType     string `json:"stream_type"`
+	StreamPosition string `json:"stream_position"`
+}
+
+// eventRecord is an exported enterprise event.
+type eventRecord struct {
+	EventID           string                 `json:"event_id"`
+	EventType         string                 `json:"event_type"`
+	CreatedAt         string                 `json:"created_at"`
+	CreatedBy         eventUser              `json:"created_by"`
+	IPAddress         string                 `json:"ip_address,omitempty"`
+	SessionID         string                 `json:"session_id,omitempty"`
+	Source            map[string]interface{} `json:"source,omitempty"`
+	AdditionalDetails map[string]interface{} `json:"additional_details,omitempty"`
+}
+
+type eventUser struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Login string `json:"login"`
+}
+
+// eventsCmd returns the subcommand that exports enterprise admin events.
+func eventsCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Export Box enterprise admin events as JSONL or CSV",
+		Long: "Export Box enterprise admin events as JSONL or CSV.\n\n" +
+			"Without --start-time, --stream-position or an existing checkpoint, --follow is required and events are streamed from now on. " +
+			"With --checkpoint the stream position is saved after every chunk and a later run resumes from it, " +
+			"ignoring --start-time and --stream-position.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			ctx, client, _, err := setupSubcommand(ctx, cmd)
+			if err != nil {
+				return err
+			}
+
+			return runEvents(ctx, cmd, client)
+		},
+	}
+
+	cmd.Flags().String("start-time", "", "Export events created at or after this RFC 3339 time.")
+	cmd.Flags().String("end-time", "", "Export events created before this RFC 3339 time. Requires --start-time.")
+	cmd.Flags().String("stream-position", "", "Stream position to start from, as written to the checkpoint file.")
+	cmd.Flags().StringSlice("event-type", nil, "Only export events of these types, e.g. LOGIN,ADD_LOGIN_ACTIVITY_DEVICE.")
+	cmd.Flags().String("user", "", "Only export events created by or about the user with this ID or login.")
+	cmd.Flags().String("group", "", "Only export events about the group with this ID.")
+	cmd.Flags().String("format", eventsFormatJSONL, "Output format: jsonl or csv.")
+	cmd.Flags().StringP("output", "o", "", "File to append the events to. Defaults to stdout.")
+	cmd.Flags().String("checkpoint", "", "File used to save and resume the stream position.")
+	cmd.Flags().Bool("follow", false, "Keep polling for new events after the end of the stream is reached.")
+	cmd.Flags().Duration("poll-interval", 30*time.Second, "How long to wait between polls with --follow.")
+
+	return cmd
+}
+
+func runEvents(ctx context.Context, cmd *cobra.Command, client *box.Client) error {
+	l := ctxzap.Extract(ctx)
+	flags := cmd.Flags()
+
+	startTime, _ := flags.GetString("start-time")
+	endTime, _ := flags.GetString("end-time")
+	streamPosition, _ := flags.GetString("stream-position")
+	eventTypes, _ := flags.GetStringSlice("event-type")
+	user, _ := flags.GetString("user")
+	group, _ := flags.GetString("group")
+	format, _ := flags.GetString("format")
+	output, _ := flags.GetString("output")
+	checkpointPath, _ := flags.GetString("checkpoint")
+	follow, _ := flags.GetBool("follow")
+	pollInterval, _ := flags.GetDuration("poll-interval")
+
+	if format != eventsFormatJSONL && format != eventsFormatCSV {
+		return fmt.Errorf("format must be one of %s or %s", eventsFormatJSONL, eventsFormatCSV)
+	}
+	if startTime != "" && streamPosition != "" {
+		return fmt.Errorf("start time and stream position cannot be used together")
+	}
+	if endTime != "" && startTime == "" {
+		return fmt.Errorf("end time requires a start time")
+	}
+	if endTime != "" && follow {
+		return fmt.Errorf("end time cannot be used with follow")
+	}
+	if pollInterval <= 0 {
+		return fmt.Errorf("poll interval must be positive")
+	}
+
+	query := box.EventsQuery{
+		StreamType:     box.StreamTypeAdminLogsStreaming,
+		StreamPosition: box.StreamPositionNow,
+		EventTypes:     eventTypes,
+	}
+
+	switch {
+	case startTime != "":
+		createdAfter, err := time.Parse(time.RFC3339, startTime)
+		if err != nil {
+			return fmt.Errorf("invalid start time: %w", err)
+		}
+		query.StreamType = box.StreamTypeAdminLogs
+		query.StreamPosition = ""
+		query.CreatedAfter = createdAfter
+
+		if endTime != "" {
+			createdBefore, err := time.Parse(time.RFC3339, endTime)
+			if err != nil {
+				return fmt.Errorf("invalid end time: %w", err)
+			}
+			query.CreatedBefore = createdBefore
+		}
+	case streamPosition != "":
+		query.StreamPosition = streamPosition
+	}
+
+	var checkpoint *eventsCheckpoint
+	if checkpointPath != "" {
+		var err error
+		checkpoint, err = readEventsCheckpoint(checkpointPath)
+		if err != nil {
+			return err
+		}
+		if checkpoint != nil {
+			l.Info("resuming from checkpoint",
+				zap.String("stream_type", checkpoint.StreamType),
+				zap.String("stream_position", checkpoint.StreamPosition),
+			)
+			// rerunning the command of the first run resumes it, so the start flags are ignored rather than rejected.
+			for _, flag := range []string{"start-time", "stream-position"} {
+				if flags.Changed(flag) {
+					l.Warn("checkpoint found, ignoring --"+flag, zap.String("checkpoint", checkpointPath))
+				}
+			}
+			query.StreamType = checkpoint.StreamType
+			query.StreamPosition = checkpoint.StreamPosition
+		}
+	}
+
+	// starting at now only returns events that happen while the command runs.
+	if startTime == "" && streamPosition == "" && checkpoint == nil && !follow {
+		return fmt.Errorf("nothing to export: give --start-time, --stream-position, a --checkpoint of a previous run or --follow")
+	}
+
+	var out io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w, err := newEventWriter(out, format)
+	if err != nil {
+		return err
+	}
+
+	for {
+		events, nextPosition, err := client.GetEnterpriseEvents(ctx, query)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return err
+		}
+
+		for _, event := range events {
+			if !eventMatches(event, user, group) {
+				continue
+			}
+			if err := w.write(event); err != nil {
+				return fmt.Errorf("failed to write event %s: %w", event.EventID, err)
+			}
+		}
+		if err := w.flush(); err != nil {
+			return fmt.Errorf("failed to write events: %w", err)
+		}
+
+		if nextPosition != "" {
+			query.StreamPosition = nextPosition
+		}
+
+		if checkpointPath != "" {
+			err := writeEventsCheckpoint(checkpointPath, eventsCheckpoint{
+				StreamType:     query.StreamType,
+				StreamPosition: query.StreamPosition,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(events) > 0 {
+			continue
+		}
+		if !follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// eventMatches reports whether the event concerns the given user and group. Empty filters match every event.
+func eventMatches(event box.Event, user, group string) bool {
+	if user != "" {
+		createdBy := event.CreatedBy.ID == user || event.CreatedBy.Login == user
+		source := eventSourceIs(event.Source, "user", user) ||
+			stringValue(event.Source, "login") == user
+		if !createdBy && !source {
+			return false
+		}
+	}
+
+	if group != "" {
+		source := eventSourceIs(event.Source, "group", group) ||
+			stringValue(event.Source, "group_id") == group
+		details := stringValue(event.AdditionalDetails, "group_id") == group
+		if !source && !details {
+			return false
+		}
+	}
+
+	return true
+}
+
+// eventSourceIs reports whether the event source is the item of the given type and ID.
+func eventSourceIs(source map[string]interface{}, itemType, id string) bool {
+	if stringValue(source, "type") == itemType && stringValue(source, "id") == id {
+		return true
+	}
+	// admin events about users carry the user ID in a dedicated field.
+	return stringValue(source, itemType+"_id") == id
+}
+
+func stringValue(m map[string]interface{}, key string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return ""
+	}
+}
+
+type eventWriter struct {
+	jsonEncoder *json.Encoder
+	csvWriter   *csv.Writer
+}
+
+func newEventWriter(out io.Writer, format string) (*eventWriter, error) {
+	if format == eventsFormatJSONL {
+		return &eventWriter{jsonEncoder: json.NewEncoder(out)}, nil
+	}
+
+	w := &eventWriter{csvWriter: csv.NewWriter(out)}
+	// the header is only written to new or empty outputs, so appending to an existing file keeps it valid.
+	if f, ok := out.(*os.File); ok {
+		if fi, err := f.Stat(); err == nil && fi.Mode().IsRegular() && fi.Size() > 0 {
+			return w, nil
+		}
+	}
+	if err := w.csvWriter.Write(eventsCSVHeader); err != nil {
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	return w, nil
+}
+
+func (w *eventWriter) write(event box.Event) error {
+	record := eventRecord{
+		EventID:   event.EventID,
+		EventType: event.EventType,
+		CreatedAt: event.CreatedAt,
+		CreatedBy: eventUser{
+			ID:    event.CreatedBy.ID,
+			Type:  event.CreatedBy.Type,
+			Name:  event.CreatedBy.Name,
+			Login: event.CreatedBy.Login,
+		},
+		IPAddress:         event.IPAddress,
+		SessionID:         event.SessionID,
+		Source:            event.Source,
+		AdditionalDetails: event.AdditionalDetails,
+	}
+
+	if w.jsonEncoder != nil {
+		return w.jsonEncoder.Encode(record)
+	}
+
+	var details string
+	if len(record.AdditionalDetails) > 0 {
+		b, err := json.Marshal(record.AdditionalDetails)
+		if err != nil {
+			return err
+		}
+		details = string(b)
+	}
+
+	return w.csvWriter.Write([]string{
+		record.EventID,
+		record.EventType,
+		record.CreatedAt,
+		record.CreatedBy.ID,
+		record.CreatedBy.Login,
+		stringValue(record.Source, "type"),
+		stringValue(record.Source, "id"),
+		record.IPAddress,
+		details,
+	})
+}
+
+func (w *eventWriter) flush() error {
+	if w.csvWriter == nil {
+		return nil
+	}
+	w.csvWriter.Flush()
+	return w.csvWriter.Error()
+}
+
+// readEventsCheckpoint returns the saved stream position, or nil when no checkpoint was written yet.
+func readEventsCheckpoint(path string) (*eventsCheckpoint, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	checkpoint := &eventsCheckpoint{}
+	if err := json.Unmarshal(b, checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	if checkpoint.StreamType == "" || checkpoint.StreamPosition == "" {
+		return nil, fmt.Errorf("invalid checkpoint %s: stream type and position are required", path)
+	}
+
+	return checkpoint, nil
+}
+
+// writeEventsCheckpoint replaces the checkpoint atomically, so an interrupted run never leaves a partial file behind.
+func writeEventsCheckpoint(path string, checkpoint eventsCheckpoint) error {
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// groupsCmd returns the subcommand that manages the lifecycle of Box groups.
+func groupsCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "groups",
+		Short: "Create, update and delete Box groups",
+	}
+
+	cmd.AddCommand(
+		groupsCreateCmd(ctx),
+		groupsUpdateCmd(ctx),
+		groupsDeleteCmd(ctx),
+	)
+
+	return cmd
+}
+
+func groupsCreateCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a Box group and print it as JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			request, err := groupRequestFromFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			if request.Name == nil || *request.Name == "" {
+				return fmt.Errorf("name is required")
+			}
+
+			ctx, client, _, err := setupSubcommand(ctx, cmd)
+			if err != nil {
+				return err
+			}
+
+			group, err := client.CreateGroup(ctx, request)
+			if err != nil {
+				return err
+			}
+
+			return writeJSON(os.Stdout, group)
+		},
+	}
+
+	groupFlags(cmd.Flags())
+
+	return cmd
+}
+
+func groupsUpdateCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update <group-id>",
+		Short: "Update the given fields of a Box group and print it as JSON",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			request, err := groupRequestFromFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			if request == (box.GroupRequest{}) {
+				return fmt.Errorf("at least one field to update is required")
+			}
+
+			ctx, client, _, err := setupSubcommand(ctx, cmd)
+			if err != nil {
+				return err
+			}
+
+			group, err := client.UpdateGroup(ctx, args[0], request)
+			if err != nil {
+				return err
+			}
+
+			return writeJSON(os.Stdout, group)
+		},
+	}
+
+	groupFlags(cmd.Flags())
+
+	return cmd
+}
+
+func groupsDeleteCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <group-id>",
+		Short: "Delete a Box group and all of its memberships",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, client, _, err := setupSubcommand(ctx, cmd)
+			if err != nil {
+				return err
+			}
+
+			return client.DeleteGroup(ctx, args[0])
+		},
+	}
+
+	return cmd
+}
+
+// groupFlags adds the flags of the writable group fields.
+func groupFlags(flags *pflag.FlagSet) {
+	flags.String("name", "", "Name of the group.")
+	flags.String("description", "", "Description of the group.")
+	flags.String("provenance", "", "External source the group is synced from, e.g. Active Directory.")
+	flags.String("external-sync-identifier", "", "ID of the group in the external source it is synced from.")
+	flags.String("invitability-level", "", "Who can add members: admins_only, admins_and_members or all_managed_users.")
+	flags.String("member-viewability-level", "", "Who can see the members: admins_only, admins_and_members or all_managed_users.")
+}
+
+// groupRequestFromFlags returns a request with the group fields set on the command line, so updates leave other fields unchanged.
+func groupRequestFromFlags(flags *pflag.FlagSet) (box.GroupRequest, error) {
+	var request box.GroupRequest
+
+	fields := []struct {
+		flag  string
+		field **string
+		level bool
+	}{
+		{flag: "name", field: &request.Name},
+		{flag: "description", field: &request.Description},
+		{flag: "provenance", field: &request.Provenance},
+		{flag: "external-sync-identifier", field: &request.ExternalSyncIdentifier},
+		{flag: "invitability-level", field: &request.InvitabilityLevel, level: true},
+		{flag: "member-viewability-level", field: &request.MemberViewabilityLevel, level: true},
+	}
+
+	for _, f := range fields {
+		if !flags.Changed(f.flag) {
+			continue
+		}
+
+		value, err := flags.GetString(f.flag)
+		if err != nil {
+			return box.GroupRequest{}, err
+		}
+
+		if f.level {
+			switch value {
+			case box.GroupLevelAdminsOnly, box.GroupLevelAdminsAndMembers, box.GroupLevelAllManagedUsers:
+			default:
+				return box.GroupRequest{}, fmt.Errorf(
+					"%s must be one of %s, %s or %s",
+					f.flag,
+					box.GroupLevelAdminsOnly,
+					box.GroupLevelAdminsAndMembers,
+					box.GroupLevelAllManagedUsers,
+				)
+			}
+		}
+
+		*f.field = &value
+	}
+
+	return request, nil
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -27,6 +27,13 @@ func main() {
 
 	cmd.Version = version
 	cmdFlags(cmd)
+	cmd.AddCommand(
+		eventsCmd(ctx),
+		groupsCmd(ctx),
+		reconcileCmd(ctx),
+		offboardCmd(ctx),
+		usersCmd(ctx),
+	)
 
 	err = cmd.Execute()
 	if err != nil {
@@ -38,7 +45,16 @@ func main() {
 func getConnector(ctx context.Context, cfg *config) (types.ConnectorServer, error) {
 	l := ctxzap.Extract(ctx)
 
-	c, err := connector.New(ctx, cfg.ClientID, cfg.ClientSecret, cfg.EnterpriseID)
+	c, err := connector.New(
+		ctx,
+		cfg.ClientID,
+		cfg.ClientSecret,
+		cfg.EnterpriseID,
+		cfg.UserType,
+		cfg.UserFields,
+		cfg.ActivityLookback,
+		cfg.AllowExternalGroupChanges,
+	)
 	if err != nil {
 		l.Error("error creating box connector", zap.Error(err))
 		return nil, err
@@ -0,0 +1,509 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	"github.com/spf13/cobra"
+)
+
+const (
+	stepStatusPending = "pending"
+	stepStatusDone    = "done"
+	stepStatusSkipped = "skipped"
+	stepStatusFailed  = "failed"
+
+	stepGroupMemberships = "remove_group_memberships"
+	stepCollaborations   = "collaborations"
+	stepContentTransfer  = "transfer_owned_content"
+	stepDevicePins       = "remove_device_pins"
+	stepEmailAliases     = "remove_email_aliases"
+	stepAccount          = "disable_account"
+
+	finalActionDeactivate = "deactivate"
+	finalActionDelete     = "delete"
+
+	itemTypeFolder = "folder"
+	itemTypeFile   = "file"
+)
+
+// offboardSteps are the offboarding steps in the order they run. Content is transferred before the account is
+// deactivated or deleted, so a failure never leaves a deleted user with content behind.
+var offboardSteps = []string{
+	stepGroupMemberships,
+	stepCollaborations,
+	stepContentTransfer,
+	stepDevicePins,
+	stepEmailAliases,
+	stepAccount,
+}
+
+// offboardReport is the JSON report of an offboarding run. It doubles as the state a failed run is resumed from.
+type offboardReport struct {
+	UserID     string          `json:"user_id"`
+	Login      string          `json:"login"`
+	StartedAt  string          `json:"started_at"`
+	FinishedAt string          `json:"finished_at,omitempty"`
+	Steps      []*offboardStep `json:"steps"`
+}
+
+type offboardStep struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// StartedAt is set when the step started an operation Box keeps running after the run ends, e.g. on a timeout.
+	StartedAt string   `json:"started_at,omitempty"`
+	Actions   []string `json:"actions,omitempty"`
+	Detail    string   `json:"detail,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// offboardClient is the part of the Box client needed to offboard a user.
+type offboardClient interface {
+	userResolver
+	contentTransferrer
+	GetUserGroupMemberships(ctx context.Context, userId string) ([]box.GroupMembership, error)
+	RemoveGroupMembership(ctx context.Context, membershipId string) error
+	GetPendingCollaborationsAsUser(ctx context.Context, userId string) ([]box.Collaboration, error)
+	RejectCollaborationAsUser(ctx context.Context, userId string, collaborationId string) error
+	GetFolderItemsAsUser(ctx context.Context, userId string, folderId string) ([]box.Item, error)
+	GetItemCollaborationsAsUser(ctx context.Context, userId string, item box.Item) ([]box.Collaboration, error)
+	DeleteCollaborationAsUser(ctx context.Context, userId string, collaborationId string) error
+	GetDevicePins(ctx context.Context, enterpriseId string) ([]box.DevicePin, error)
+	DeleteDevicePin(ctx context.Context, pinId string) error
+	GetEmailAliases(ctx context.Context, userId string) ([]box.EmailAlias, error)
+	DeleteEmailAlias(ctx context.Context, userId string, aliasId string) error
+	SetUserStatus(ctx context.Context, userId string, status string) error
+	DeleteUser(ctx context.Context, userId string, force bool) error
+}
+
+type offboardOptions struct {
+	custodian          string
+	notifyCustodian    bool
+	transferTimeout    time.Duration
+	removeDevicePins   bool
+	removeEmailAliases bool
+	finalAction        string
+	forceDelete        bool
+	enterpriseID       string
+}
+
+// offboardCmd returns the subcommand that offboards a Box user.
+func offboardCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "offboard",
+		Short: "Offboard a Box user and print a JSON report of every step",
+		Long: "Offboard a Box user and print a JSON report of every step.\n\n" +
+			"The user is removed from all groups and from content of other users they collaborate on, their owned content " +
+			"is transferred to a custodian, their device pins and email aliases are removed, and the account is " +
+			"deactivated or deleted. Collaborations are found by acting as the user, which requires the Box application " +
+			"to be allowed to perform actions as users, otherwise the step is skipped. The run stops at the first failed " +
+			"step. With --state the report is saved after every step, and running the command again with the same state " +
+			"file resumes at the failed step.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			userKey, _ := flags.GetString("user")
+			statePath, _ := flags.GetString("state")
+
+			opts := offboardOptions{}
+			opts.custodian, _ = flags.GetString("custodian")
+			opts.notifyCustodian, _ = flags.GetBool("notify-custodian")
+			opts.transferTimeout, _ = flags.GetDuration("transfer-timeout")
+			opts.removeDevicePins, _ = flags.GetBool("remove-device-pins")
+			opts.removeEmailAliases, _ = flags.GetBool("remove-email-aliases")
+			opts.finalAction, _ = flags.GetString("final-action")
+			opts.forceDelete, _ = flags.GetBool("force-delete")
+
+			if userKey == "" {
+				return fmt.Errorf("user is required")
+			}
+			if opts.finalAction != finalActionDeactivate && opts.finalAction != finalActionDelete {
+				return fmt.Errorf("final action must be one of %s or %s", finalActionDeactivate, finalActionDelete)
+			}
+			if opts.transferTimeout <= 0 {
+				return fmt.Errorf("transfer timeout must be positive")
+			}
+
+			ctx, client, cfg, err := setupSubcommand(ctx, cmd)
+			if err != nil {
+				return err
+			}
+			opts.enterpriseID = cfg.EnterpriseID
+
+			report, err := loadOffboardReport(ctx, client, statePath, userKey)
+			if err != nil {
+				return err
+			}
+
+			runErr := runOffboard(ctx, client, report, opts, func() error {
+				return saveOffboardReport(statePath, report)
+			})
+
+			if err := writeJSON(os.Stdout, report); err != nil {
+				return err
+			}
+
+			return runErr
+		},
+	}
+
+	cmd.Flags().String("user", "", "Login or ID of the user to offboard.")
+	cmd.Flags().String("custodian", "", "Login or ID of the user receiving the owned content. Content is not transferred when empty.")
+	cmd.Flags().Bool("notify-custodian", false, "Notify the custodian by email about the transferred content.")
+	cmd.Flags().Duration("transfer-timeout", defaultTransferTimeout, "How long to wait for Box to transfer the owned content to the custodian.")
+	cmd.Flags().Bool("remove-device-pins", true, "Remove the device pins of the user.")
+	cmd.Flags().Bool("remove-email-aliases", true, "Remove the email aliases of the user.")
+	cmd.Flags().String("final-action", finalActionDeactivate, "What to do with the account at the end: deactivate or delete.")
+	cmd.Flags().Bool("force-delete", false, "Delete the user even if they still own content, which deletes that content.")
+	cmd.Flags().String("state", "", "File used to save the report after every step and resume a failed run.")
+
+	return cmd
+}
+
+// loadOffboardReport resumes the report saved in the state file, or starts a new one for the user.
+func loadOffboardReport(ctx context.Context, client userResolver, statePath string, userKey string) (*offboardReport, error) {
+	if statePath != "" {
+		b, err := os.ReadFile(statePath)
+		switch {
+		case err == nil:
+			report := &offboardReport{}
+			if err := json.Unmarshal(b, report); err != nil {
+				return nil, fmt.Errorf("invalid state %s: %w", statePath, err)
+			}
+			if report.UserID != userKey && report.Login != userKey {
+				return nil, fmt.Errorf("state %s belongs to user %s, not %s", statePath, report.Login, userKey)
+			}
+			return report, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, fmt.Errorf("failed to read state: %w", err)
+		}
+	}
+
+	user, err := resolveUser(ctx, client, userKey)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &offboardReport{
+		UserID:    user.ID,
+		Login:     user.Login,
+		StartedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, name := range offboardSteps {
+		report.Steps = append(report.Steps, &offboardStep{Name: name, Status: stepStatusPending})
+	}
+
+	return report, nil
+}
+
+// saveOffboardReport replaces the state file atomically. Nothing is saved without a state file.
+func saveOffboardReport(statePath string, report *offboardReport) error {
+	if statePath == "" {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := statePath + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp, statePath); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+
+	return nil
+}
+
+// runOffboard runs all steps that are not done yet and stops at the first failure.
+func runOffboard(ctx context.Context, client offboardClient, report *offboardReport, opts offboardOptions, save func() error) error {
+	for _, step := range report.Steps {
+		if step.Status == stepStatusDone || step.Status == stepStatusSkipped {
+			continue
+		}
+
+		// actions of a previous failed attempt are kept, they already happened.
+		step.Detail = ""
+		step.Error = ""
+
+		err := runOffboardStep(ctx, client, report.UserID, step, opts, save)
+		if err != nil {
+			step.Status = stepStatusFailed
+			step.Error = err.Error()
+		} else if step.Status != stepStatusSkipped {
+			step.Status = stepStatusDone
+		}
+
+		if saveErr := save(); saveErr != nil {
+			return saveErr
+		}
+		if err != nil {
+			return fmt.Errorf("offboarding step %s failed: %w", step.Name, err)
+		}
+	}
+
+	report.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+
+	return save()
+}
+
+func runOffboardStep(
+	ctx context.Context,
+	client offboardClient,
+	userId string,
+	step *offboardStep,
+	opts offboardOptions,
+	save func() error,
+) error {
+	switch step.Name {
+	case stepGroupMemberships:
+		memberships, err := client.GetUserGroupMemberships(ctx, userId)
+		if err != nil {
+			return err
+		}
+		for _, membership := range memberships {
+			if err := client.RemoveGroupMembership(ctx, membership.ID); err != nil {
+				return err
+			}
+			step.Actions = append(step.Actions, fmt.Sprintf("removed %s membership of group %s (%s)", membership.Role, membership.Group.Name, membership.Group.ID))
+		}
+
+	case stepCollaborations:
+		return removeCollaborations(ctx, client, userId, step)
+
+	case stepContentTransfer:
+		return transferContentStep(ctx, client, userId, step, opts, save)
+
+	case stepDevicePins:
+		if !opts.removeDevicePins {
+			step.Status = stepStatusSkipped
+			return nil
+		}
+		pins, err := client.GetDevicePins(ctx, opts.enterpriseID)
+		if err != nil {
+			return err
+		}
+		for _, pin := range pins {
+			if pin.OwnedBy.ID != userId {
+				continue
+			}
+			if err := client.DeleteDevicePin(ctx, pin.ID); err != nil {
+				return err
+			}
+			step.Actions = append(step.Actions, fmt.Sprintf("removed device pin %s (%s)", pin.ID, pin.ProductName))
+		}
+
+	case stepEmailAliases:
+		if !opts.removeEmailAliases {
+			step.Status = stepStatusSkipped
+			return nil
+		}
+		aliases, err := client.GetEmailAliases(ctx, userId)
+		if err != nil {
+			return err
+		}
+		for _, alias := range aliases {
+			if err := client.DeleteEmailAlias(ctx, userId, alias.ID); err != nil {
+				return err
+			}
+			step.Actions = append(step.Actions, fmt.Sprintf("removed email alias %s", alias.Email))
+		}
+
+	case stepAccount:
+		if opts.finalAction == finalActionDelete {
+			if err := client.DeleteUser(ctx, userId, opts.forceDelete); err != nil {
+				return err
+			}
+			step.Actions = append(step.Actions, "deleted user")
+			return nil
+		}
+		if err := client.SetUserStatus(ctx, userId, box.UserStatusInactive); err != nil {
+			return err
+		}
+		step.Actions = append(step.Actions, "deactivated user")
+
+	default:
+		return fmt.Errorf("unknown step %s", step.Name)
+	}
+
+	return nil
+}
+
+// transferContentStep transfers the owned content of the user to the custodian. The step is saved as started before
+// Box is called, because Box keeps moving the content when the run times out or is interrupted. A resumed run then
+// checks where the content is instead of requesting the transfer again.
+func transferContentStep(
+	ctx context.Context,
+	client offboardClient,
+	userId string,
+	step *offboardStep,
+	opts offboardOptions,
+	save func() error,
+) error {
+	if opts.custodian == "" {
+		step.Status = stepStatusSkipped
+		step.Detail = "no custodian given"
+		return nil
+	}
+	user, custodian, err := preflightTransfer(ctx, client, opts.enterpriseID, userId, opts.custodian)
+	if err != nil {
+		return err
+	}
+
+	if step.StartedAt != "" {
+		return checkTransferredContent(ctx, client, user, custodian, step)
+	}
+
+	step.StartedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := save(); err != nil {
+		return err
+	}
+
+	transferCtx, cancel := context.WithTimeout(ctx, opts.transferTimeout)
+	defer cancel()
+	folder, err := transferOwnedContent(transferCtx, client, user, custodian, opts.notifyCustodian, defaultTransferProgressInterval)
+	if err != nil {
+		var errorResponse *box.ErrorResponse
+		if errors.As(err, &errorResponse) {
+			// Box refused the transfer, so nothing is moving and it can be requested again.
+			step.StartedAt = ""
+		}
+		return err
+	}
+	step.Actions = append(step.Actions, fmt.Sprintf("transferred owned content to %s into folder %s (%s)", custodian.Login, folder.Name, folder.ID))
+
+	return nil
+}
+
+// checkTransferredContent finishes a transfer started by an earlier run. The transfer is done once the user owns
+// nothing in their root folder, the folder Box created for the content is looked up in the root folder of the custodian.
+func checkTransferredContent(ctx context.Context, client offboardClient, user box.User, custodian box.User, step *offboardStep) error {
+	items, err := client.GetFolderItemsAsUser(ctx, user.ID, "0")
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.OwnedBy.ID == user.ID {
+			return fmt.Errorf(
+				"the transfer started at %s has not finished, run again later or remove started_at from the state to request it again",
+				step.StartedAt,
+			)
+		}
+	}
+
+	custodianItems, err := client.GetFolderItemsAsUser(ctx, custodian.ID, "0")
+	if err != nil {
+		return err
+	}
+	for _, item := range custodianItems {
+		// Box names the folder after the login of the user the content came from.
+		if item.Type == itemTypeFolder && item.OwnedBy.ID == custodian.ID && strings.Contains(item.Name, user.Login) {
+			step.Actions = append(step.Actions, fmt.Sprintf("transferred owned content to %s into folder %s (%s)", custodian.Login, item.Name, item.ID))
+			return nil
+		}
+	}
+
+	step.Detail = fmt.Sprintf("the user owns no content anymore, but no folder with the content was found in the root folder of %s", custodian.Login)
+	return nil
+}
+
+// removeCollaborations removes the user from all content of other users they collaborate on, and rejects their pending
+// invitations. Box cannot list the collaborations of a user, so the folders the user owns are walked on their behalf
+// to find the content shared with them. Collaborations on owned content move with the content transfer.
+// When the application may not act as users, the step is skipped. Folders and items Box refuses to show are reported
+// in the step detail.
+func removeCollaborations(ctx context.Context, client offboardClient, userId string, step *offboardStep) error {
+	pending, err := client.GetPendingCollaborationsAsUser(ctx, userId)
+	if err != nil {
+		if box.IsForbidden(err) {
+			step.Status = stepStatusSkipped
+			step.Detail = "the Box application is not allowed to act as users, remove the user from content of other users manually"
+			return nil
+		}
+		return err
+	}
+	for _, collaboration := range pending {
+		if err := client.RejectCollaborationAsUser(ctx, userId, collaboration.ID); err != nil {
+			return err
+		}
+		step.Actions = append(step.Actions, fmt.Sprintf("rejected pending %s collaboration on %s %s (%s)",
+			collaboration.Role, collaboration.Item.Type, collaboration.Item.Name, collaboration.Item.ID))
+	}
+
+	var shared []box.Item
+	var forbidden []string
+	folders := []string{"0"}
+	for len(folders) > 0 {
+		folderId := folders[0]
+		folders = folders[1:]
+
+		items, err := client.GetFolderItemsAsUser(ctx, userId, folderId)
+		if err != nil {
+			if box.IsForbidden(err) {
+				forbidden = append(forbidden, fmt.Sprintf("folder %s", folderId))
+				continue
+			}
+			return err
+		}
+
+		for _, item := range items {
+			if item.Type != itemTypeFolder && item.Type != itemTypeFile {
+				continue
+			}
+			switch {
+			case item.OwnedBy.ID != userId:
+				// access to the content below a shared folder comes from the collaboration on the folder.
+				shared = append(shared, item)
+			case item.Type == itemTypeFolder:
+				folders = append(folders, item.ID)
+			}
+		}
+	}
+
+	var unresolved []string
+	for _, item := range shared {
+		collaborations, err := client.GetItemCollaborationsAsUser(ctx, userId, item)
+		if err != nil {
+			if box.IsForbidden(err) {
+				forbidden = append(forbidden, fmt.Sprintf("%s %s (%s)", item.Type, item.Name, item.ID))
+				continue
+			}
+			return err
+		}
+
+		removed := false
+		for _, collaboration := range collaborations {
+			if collaboration.AccessibleBy.Type != "user" || collaboration.AccessibleBy.ID != userId {
+				continue
+			}
+			if err := client.DeleteCollaborationAsUser(ctx, userId, collaboration.ID); err != nil {
+				return err
+			}
+			step.Actions = append(step.Actions, fmt.Sprintf("removed %s collaboration on %s %s (%s)",
+				collaboration.Role, item.Type, item.Name, item.ID))
+			removed = true
+		}
+
+		if !removed {
+			unresolved = append(unresolved, fmt.Sprintf("%s %s (%s)", item.Type, item.Name, item.ID))
+		}
+	}
+
+	if len(forbidden) > 0 {
+		step.Detail = fmt.Sprintf("Box refused to show %s, check the access of the user there manually", strings.Join(forbidden, ", "))
+	}
+	if len(unresolved) > 0 {
+		return fmt.Errorf("no collaboration of the user found on %s, remove their access manually", strings.Join(unresolved, ", "))
+	}
+
+	return nil
+}
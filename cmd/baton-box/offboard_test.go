@@ -0,0 +1,505 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-box/pkg/box"
+)
+
+// fakeOffboardClient records the changing Box calls and fails the calls named in errs.
+type fakeOffboardClient struct {
+	users          map[string]box.User
+	memberships    []box.GroupMembership
+	pending        []box.Collaboration
+	folderItems    map[string][]box.Item
+	collaborations map[string][]box.Collaboration
+	pins           []box.DevicePin
+	aliases        []box.EmailAlias
+	errs           map[string]error
+
+	calls []string
+}
+
+func (f *fakeOffboardClient) call(name string, args ...string) error {
+	f.calls = append(f.calls, strings.TrimSpace(name+" "+strings.Join(args, " ")))
+	return f.errs[name]
+}
+
+func (f *fakeOffboardClient) GetUser(_ context.Context, userId string) (box.User, error) {
+	user, ok := f.users[userId]
+	if !ok {
+		return box.User{}, fmt.Errorf("user %s not found", userId)
+	}
+	return user, nil
+}
+
+func (f *fakeOffboardClient) GetUserByLogin(_ context.Context, login string) (box.User, error) {
+	for _, user := range f.users {
+		if user.Login == login {
+			return user, nil
+		}
+	}
+	return box.User{}, fmt.Errorf("user %s not found", login)
+}
+
+func (f *fakeOffboardClient) TransferOwnedContent(_ context.Context, fromUserId string, toUserId string, _ bool) (box.Folder, error) {
+	if err := f.call("TransferOwnedContent", fromUserId, toUserId); err != nil {
+		return box.Folder{}, err
+	}
+	return box.Folder{BaseType: box.BaseType{ID: "900", Type: "folder"}, Name: "Transferred"}, nil
+}
+
+func (f *fakeOffboardClient) GetUserGroupMemberships(_ context.Context, userId string) ([]box.GroupMembership, error) {
+	return f.memberships, f.call("GetUserGroupMemberships", userId)
+}
+
+func (f *fakeOffboardClient) RemoveGroupMembership(_ context.Context, membershipId string) error {
+	return f.call("RemoveGroupMembership", membershipId)
+}
+
+func (f *fakeOffboardClient) GetPendingCollaborationsAsUser(_ context.Context, _ string) ([]box.Collaboration, error) {
+	return f.pending, f.errs["GetPendingCollaborationsAsUser"]
+}
+
+func (f *fakeOffboardClient) RejectCollaborationAsUser(_ context.Context, _ string, collaborationId string) error {
+	return f.call("RejectCollaborationAsUser", collaborationId)
+}
+
+// GetFolderItemsAsUser returns the items keyed by "<user>/<folder>", or else by the folder ID alone.
+func (f *fakeOffboardClient) GetFolderItemsAsUser(_ context.Context, userId string, folderId string) ([]box.Item, error) {
+	if items, ok := f.folderItems[userId+"/"+folderId]; ok {
+		return items, nil
+	}
+	return f.folderItems[folderId], f.errs["GetFolderItemsAsUser "+folderId]
+}
+
+func (f *fakeOffboardClient) GetItemCollaborationsAsUser(_ context.Context, _ string, item box.Item) ([]box.Collaboration, error) {
+	return f.collaborations[item.ID], f.errs["GetItemCollaborationsAsUser "+item.ID]
+}
+
+func (f *fakeOffboardClient) DeleteCollaborationAsUser(_ context.Context, _ string, collaborationId string) error {
+	return f.call("DeleteCollaborationAsUser", collaborationId)
+}
+
+func (f *fakeOffboardClient) GetDevicePins(_ context.Context, _ string) ([]box.DevicePin, error) {
+	return f.pins, nil
+}
+
+func (f *fakeOffboardClient) DeleteDevicePin(_ context.Context, pinId string) error {
+	return f.call("DeleteDevicePin", pinId)
+}
+
+func (f *fakeOffboardClient) GetEmailAliases(_ context.Context, _ string) ([]box.EmailAlias, error) {
+	return f.aliases, nil
+}
+
+func (f *fakeOffboardClient) DeleteEmailAlias(_ context.Context, _ string, aliasId string) error {
+	return f.call("DeleteEmailAlias", aliasId)
+}
+
+func (f *fakeOffboardClient) SetUserStatus(_ context.Context, userId string, status string) error {
+	return f.call("SetUserStatus", userId, status)
+}
+
+func (f *fakeOffboardClient) DeleteUser(_ context.Context, userId string, _ bool) error {
+	return f.call("DeleteUser", userId)
+}
+
+func testItem(id string, itemType string, ownerID string) box.Item {
+	return box.Item{BaseType: box.BaseType{ID: id, Type: itemType}, Name: "item " + id, OwnedBy: box.BaseType{ID: ownerID, Type: "user"}}
+}
+
+func testCollaboration(id string, userID string, item box.Item) box.Collaboration {
+	collaboration := box.Collaboration{BaseType: box.BaseType{ID: id, Type: "collaboration"}, Item: item, Role: "editor"}
+	collaboration.AccessibleBy.ID = userID
+	collaboration.AccessibleBy.Type = "user"
+	return collaboration
+}
+
+func newFakeOffboardClient() *fakeOffboardClient {
+	enterprise := box.Enterprise{}
+	enterprise.ID = "e1"
+
+	alice := testUser("1", "alice@example.com")
+	alice.Enterprise = enterprise
+	custodian := testUser("2", "archive@example.com")
+	custodian.Enterprise = enterprise
+	custodian.Status = box.UserStatusActive
+
+	return &fakeOffboardClient{
+		users: map[string]box.User{"1": alice, "2": custodian},
+		memberships: []box.GroupMembership{
+			testMembership("m1", alice, box.GroupRoleMember),
+		},
+		pins: []box.DevicePin{
+			{BaseType: box.BaseType{ID: "p1"}, OwnedBy: alice},
+			{BaseType: box.BaseType{ID: "p2"}, OwnedBy: custodian},
+		},
+		aliases: []box.EmailAlias{
+			{BaseType: box.BaseType{ID: "a1"}, Email: "al@example.com"},
+		},
+		errs: map[string]error{},
+	}
+}
+
+// newTestReport returns a report of user 1 with the given statuses, in the order of offboardSteps.
+func newTestReport(statuses ...string) *offboardReport {
+	report := &offboardReport{UserID: "1", Login: "alice@example.com"}
+	for i, name := range offboardSteps {
+		status := stepStatusPending
+		if i < len(statuses) {
+			status = statuses[i]
+		}
+		report.Steps = append(report.Steps, &offboardStep{Name: name, Status: status})
+	}
+	return report
+}
+
+func stepStatuses(report *offboardReport) []string {
+	var rv []string
+	for _, step := range report.Steps {
+		rv = append(rv, step.Status)
+	}
+	return rv
+}
+
+func TestRunOffboard(t *testing.T) {
+	tests := []struct {
+		name         string
+		report       *offboardReport
+		opts         offboardOptions
+		errs         map[string]error
+		wantStatuses []string
+		wantCalls    []string
+		wantErr      string
+	}{
+		{
+			name:   "runs all steps",
+			report: newTestReport(),
+			opts:   offboardOptions{custodian: "archive@example.com", enterpriseID: "e1", transferTimeout: defaultTransferTimeout},
+			wantStatuses: []string{
+				stepStatusDone, stepStatusDone, stepStatusDone, stepStatusSkipped, stepStatusSkipped, stepStatusDone,
+			},
+			wantCalls: []string{
+				"GetUserGroupMemberships 1",
+				"RemoveGroupMembership m1",
+				"TransferOwnedContent 1 2",
+				"SetUserStatus 1 inactive",
+			},
+		},
+		{
+			name:   "skips done and skipped steps when resuming",
+			report: newTestReport(stepStatusDone, stepStatusDone, stepStatusSkipped, stepStatusFailed),
+			opts:   offboardOptions{removeDevicePins: true, removeEmailAliases: true, finalAction: finalActionDelete},
+			wantStatuses: []string{
+				stepStatusDone, stepStatusDone, stepStatusSkipped, stepStatusDone, stepStatusDone, stepStatusDone,
+			},
+			wantCalls: []string{
+				"DeleteDevicePin p1",
+				"DeleteEmailAlias a1",
+				"DeleteUser 1",
+			},
+		},
+		{
+			name:   "stops at the first failure",
+			report: newTestReport(stepStatusDone, stepStatusDone, stepStatusSkipped),
+			opts:   offboardOptions{removeDevicePins: true, removeEmailAliases: true},
+			errs:   map[string]error{"DeleteEmailAlias": errors.New("forbidden")},
+			wantStatuses: []string{
+				stepStatusDone, stepStatusDone, stepStatusSkipped, stepStatusDone, stepStatusFailed, stepStatusPending,
+			},
+			wantCalls: []string{
+				"DeleteDevicePin p1",
+				"DeleteEmailAlias a1",
+			},
+			wantErr: "offboarding step remove_email_aliases failed: forbidden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeOffboardClient()
+			for name, err := range tt.errs {
+				client.errs[name] = err
+			}
+
+			saves := 0
+			err := runOffboard(context.Background(), client, tt.report, tt.opts, func() error {
+				saves++
+				return nil
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				if tt.report.FinishedAt != "" {
+					t.Errorf("expected an unfinished report")
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if saves == 0 {
+				t.Errorf("expected the report to be saved")
+			}
+			if got := stepStatuses(tt.report); !reflect.DeepEqual(got, tt.wantStatuses) {
+				t.Errorf("unexpected statuses:\n got: %q\nwant: %q", got, tt.wantStatuses)
+			}
+			if !reflect.DeepEqual(client.calls, tt.wantCalls) {
+				t.Errorf("unexpected calls:\n got: %q\nwant: %q", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRunOffboardResumesFailedStep(t *testing.T) {
+	client := newFakeOffboardClient()
+	client.errs["DeleteDevicePin"] = errors.New("temporary failure")
+	report := newTestReport(stepStatusDone, stepStatusDone, stepStatusSkipped)
+	opts := offboardOptions{removeDevicePins: true}
+	save := func() error { return nil }
+
+	if err := runOffboard(context.Background(), client, report, opts, save); err == nil {
+		t.Fatal("expected the first run to fail")
+	}
+	if report.Steps[3].Error != "temporary failure" {
+		t.Fatalf("expected the failure to be reported, got %q", report.Steps[3].Error)
+	}
+
+	delete(client.errs, "DeleteDevicePin")
+	client.calls = nil
+
+	if err := runOffboard(context.Background(), client, report, opts, save); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Steps[3].Status != stepStatusDone || report.Steps[3].Error != "" {
+		t.Errorf("expected the failed step to be done, got %s %q", report.Steps[3].Status, report.Steps[3].Error)
+	}
+	if report.FinishedAt == "" {
+		t.Errorf("expected a finished report")
+	}
+
+	want := []string{"DeleteDevicePin p1", "SetUserStatus 1 inactive"}
+	if !reflect.DeepEqual(client.calls, want) {
+		t.Errorf("unexpected calls:\n got: %q\nwant: %q", client.calls, want)
+	}
+}
+
+func TestRemoveCollaborations(t *testing.T) {
+	sharedFolder := testItem("20", itemTypeFolder, "9")
+	sharedFile := testItem("30", itemTypeFile, "9")
+	groupFolder := testItem("40", itemTypeFolder, "9")
+
+	forbidden := &box.ErrorResponse{Type: "error", Status: 403, Message: "Access denied"}
+
+	tests := []struct {
+		name           string
+		collaborations map[string][]box.Collaboration
+		errs           map[string]error
+		wantCalls      []string
+		wantStatus     string
+		wantDetail     string
+		wantErr        string
+	}{
+		{
+			name: "removes collaborations of the user",
+			collaborations: map[string][]box.Collaboration{
+				"20": {testCollaboration("c2", "9", sharedFolder), testCollaboration("c3", "1", sharedFolder)},
+				"30": {testCollaboration("c4", "1", sharedFile)},
+				"40": {testCollaboration("c5", "1", groupFolder)},
+			},
+			wantCalls: []string{
+				"RejectCollaborationAsUser c1",
+				"DeleteCollaborationAsUser c4",
+				"DeleteCollaborationAsUser c3",
+				"DeleteCollaborationAsUser c5",
+			},
+		},
+		{
+			name: "fails when access does not come from a collaboration of the user",
+			collaborations: map[string][]box.Collaboration{
+				"20": {testCollaboration("c3", "1", sharedFolder)},
+				"30": {testCollaboration("c4", "1", sharedFile)},
+			},
+			wantCalls: []string{
+				"RejectCollaborationAsUser c1",
+				"DeleteCollaborationAsUser c4",
+				"DeleteCollaborationAsUser c3",
+			},
+			wantErr: "no collaboration of the user found on folder item 40 (40)",
+		},
+		{
+			name:       "skips the step when the application cannot act as users",
+			errs:       map[string]error{"GetPendingCollaborationsAsUser": forbidden},
+			wantStatus: stepStatusSkipped,
+			wantDetail: "the Box application is not allowed to act as users",
+		},
+		{
+			name: "reports folders and items Box refuses to show",
+			collaborations: map[string][]box.Collaboration{
+				"30": {testCollaboration("c4", "1", sharedFile)},
+			},
+			errs: map[string]error{
+				"GetFolderItemsAsUser 10":        forbidden,
+				"GetItemCollaborationsAsUser 30": forbidden,
+			},
+			wantCalls: []string{
+				"RejectCollaborationAsUser c1",
+			},
+			wantDetail: "Box refused to show folder 10, file item 30 (30)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeOffboardClient()
+			client.pending = []box.Collaboration{testCollaboration("c1", "1", testItem("50", itemTypeFolder, "9"))}
+			client.folderItems = map[string][]box.Item{
+				"0": {
+					testItem("10", itemTypeFolder, "1"),
+					sharedFile,
+					testItem("60", "web_link", "9"),
+				},
+				"10": {
+					sharedFolder,
+					testItem("11", itemTypeFile, "1"),
+					groupFolder,
+				},
+			}
+			client.collaborations = tt.collaborations
+			for name, err := range tt.errs {
+				client.errs[name] = err
+			}
+
+			step := &offboardStep{Name: stepCollaborations}
+			err := removeCollaborations(context.Background(), client, "1", step)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(client.calls, tt.wantCalls) {
+				t.Errorf("unexpected calls:\n got: %q\nwant: %q", client.calls, tt.wantCalls)
+			}
+			if len(step.Actions) != len(tt.wantCalls) {
+				t.Errorf("expected %d actions, got %d", len(tt.wantCalls), len(step.Actions))
+			}
+			if step.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, step.Status)
+			}
+			if !strings.HasPrefix(step.Detail, tt.wantDetail) || (tt.wantDetail == "" && step.Detail != "") {
+				t.Errorf("expected detail %q, got %q", tt.wantDetail, step.Detail)
+			}
+		})
+	}
+}
+
+func TestTransferContentStep(t *testing.T) {
+	opts := offboardOptions{custodian: "archive@example.com", enterpriseID: "e1", transferTimeout: defaultTransferTimeout}
+	transferred := testItem("900", itemTypeFolder, "2")
+	transferred.Name = "alice@example.com - Alice's Files and Folders"
+
+	tests := []struct {
+		name          string
+		startedAt     string
+		folderItems   map[string][]box.Item
+		transferErr   error
+		wantCalls     []string
+		wantStarted   bool
+		wantActions   int
+		wantErr       string
+		wantSavedOnce bool
+	}{
+		{
+			name:          "saves the step as started before transferring",
+			wantCalls:     []string{"TransferOwnedContent 1 2"},
+			wantStarted:   true,
+			wantActions:   1,
+			wantSavedOnce: true,
+		},
+		{
+			name:          "keeps the step started when the run times out",
+			transferErr:   context.DeadlineExceeded,
+			wantCalls:     []string{"TransferOwnedContent 1 2"},
+			wantStarted:   true,
+			wantErr:       "deadline exceeded",
+			wantSavedOnce: true,
+		},
+		{
+			name:          "clears the start when Box refuses the transfer",
+			transferErr:   &box.ErrorResponse{Type: "error", Status: 400, Message: "bad request"},
+			wantCalls:     []string{"TransferOwnedContent 1 2"},
+			wantErr:       "bad request",
+			wantSavedOnce: true,
+		},
+		{
+			name:      "finds the transferred folder instead of transferring again",
+			startedAt: "2026-10-19T08:00:00Z",
+			folderItems: map[string][]box.Item{
+				"1/0": {testItem("20", itemTypeFolder, "9")},
+				"2/0": {testItem("800", itemTypeFolder, "2"), transferred},
+			},
+			wantStarted: true,
+			wantActions: 1,
+		},
+		{
+			name:      "fails while the user still owns content",
+			startedAt: "2026-10-19T08:00:00Z",
+			folderItems: map[string][]box.Item{
+				"1/0": {testItem("10", itemTypeFolder, "1")},
+			},
+			wantStarted: true,
+			wantErr:     "the transfer started at 2026-10-19T08:00:00Z has not finished",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeOffboardClient()
+			client.folderItems = tt.folderItems
+			if tt.transferErr != nil {
+				client.errs["TransferOwnedContent"] = tt.transferErr
+			}
+
+			step := &offboardStep{Name: stepContentTransfer, Status: stepStatusFailed, StartedAt: tt.startedAt}
+			saves := 0
+			save := func() error {
+				saves++
+				if step.StartedAt == "" || len(client.calls) != 0 {
+					t.Errorf("expected the step to be saved as started before calling Box")
+				}
+				return nil
+			}
+
+			err := transferContentStep(context.Background(), client, "1", step, opts, save)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(client.calls, tt.wantCalls) {
+				t.Errorf("unexpected calls:\n got: %q\nwant: %q", client.calls, tt.wantCalls)
+			}
+			if (step.StartedAt != "") != tt.wantStarted {
+				t.Errorf("unexpected started at %q", step.StartedAt)
+			}
+			if len(step.Actions) != tt.wantActions {
+				t.Errorf("expected %d actions, got %q", tt.wantActions, step.Actions)
+			}
+			if (saves == 1) != tt.wantSavedOnce {
+				t.Errorf("unexpected number of saves: %d", saves)
+			}
+		})
+	}
+}
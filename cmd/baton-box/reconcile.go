@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	changeAdd    = "add"
+	changeUpdate = "update"
+	changeRemove = "remove"
+)
+
+// desiredState declares Box groups and their members. Groups that are not declared are left untouched.
+type desiredState struct {
+	Groups []desiredGroup `yaml:"groups"`
+}
+
+// desiredGroup declares all members of a Box group. Users are given by login or ID.
+// Members that are not declared are removed from the group.
+type desiredGroup struct {
+	// ID selects the group when several groups share a name.
+	ID      string   `yaml:"id"`
+	Name    string   `yaml:"name"`
+	Members []string `yaml:"members"`
+	Admins  []string `yaml:"admins"`
+}
+
+// membershipChange is a change to a group membership needed to reach the desired state.
+type membershipChange struct {
+	action       string
+	group        box.Group
+	user         box.User
+	role         string
+	previousRole string
+	membershipID string
+}
+
+// reconcilePlan is the list of changes per group, in the order of the desired state.
+type reconcilePlan struct {
+	groups  []box.Group
+	changes map[string][]membershipChange
+	skipped map[string]string
+}
+
+// reconcileClient is the part of the Box client needed to plan a reconciliation.
+type reconcileClient interface {
+	GetGroups(ctx context.Context) ([]box.Group, error)
+	GetUsers(ctx context.Context) ([]box.User, error)
+	GetGroupMemberships(ctx context.Context, groupId string) ([]box.GroupMembership, error)
+}
+
+// reconcileCmd returns the subcommand that reconciles Box group memberships with a desired state file.
+func reconcileCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reconcile <file>",
+		Short: "Reconcile Box group memberships with a YAML or JSON desired state file",
+		Long: "Reconcile Box group memberships with a YAML or JSON desired state file.\n\n" +
+			"The file lists groups by name or ID with all of their members and admins, given by login or user ID. " +
+			"Members of these groups that are not listed are removed, groups that are not listed are left untouched. " +
+			"The changes are only printed unless --apply is given.\n\n" +
+			"groups:\n" +
+			"  - name: Engineering\n" +
+			"    admins: [alice@example.com]\n" +
+			"    members: [bob@example.com, \"12345\"]",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state, err := readDesiredState(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx, client, cfg, err := setupSubcommand(ctx, cmd)
+			if err != nil {
+				return err
+			}
+
+			plan, err := planReconcile(ctx, client, state, cfg.AllowExternalGroupChanges)
+			if err != nil {
+				return err
+			}
+
+			printPlan(os.Stdout, plan)
+
+			apply, _ := cmd.Flags().GetBool("apply")
+			if !apply {
+				return nil
+			}
+
+			return applyPlan(ctx, os.Stdout, client, plan)
+		},
+	}
+
+	cmd.Flags().Bool("apply", false, "Apply the changes instead of only printing them.")
+
+	return cmd
+}
+
+// readDesiredState reads the desired state from a YAML file. JSON files are read as well, as JSON is valid YAML.
+func readDesiredState(path string) (*desiredState, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read desired state: %w", err)
+	}
+
+	// unknown keys are rejected, a misspelled members list would otherwise remove every member of the group.
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+
+	state := &desiredState{}
+	if err := decoder.Decode(state); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid desired state %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for i, group := range state.Groups {
+		if group.ID == "" && group.Name == "" {
+			return nil, fmt.Errorf("invalid desired state %s: group %d has neither a name nor an ID", path, i+1)
+		}
+
+		key := groupKey(group)
+		if seen[key] {
+			return nil, fmt.Errorf("invalid desired state %s: group %s is declared twice", path, key)
+		}
+		seen[key] = true
+	}
+
+	return state, nil
+}
+
+func groupKey(group desiredGroup) string {
+	if group.ID != "" {
+		return group.ID
+	}
+	return group.Name
+}
+
+// planReconcile computes the membership changes needed to reach the desired state.
+func planReconcile(ctx context.Context, client reconcileClient, state *desiredState, allowExternalGroupChanges bool) (*reconcilePlan, error) {
+	groups, err := client.GetGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := client.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usersByKey := make(map[string]box.User, 2*len(users))
+	for _, user := range users {
+		usersByKey[user.ID] = user
+		usersByKey[strings.ToLower(user.Login)] = user
+	}
+
+	plan := &reconcilePlan{
+		changes: make(map[string][]membershipChange),
+		skipped: make(map[string]string),
+	}
+
+	// a group declared once by ID and once by name resolves to the same Box group, its changes would be applied twice.
+	declared := make(map[string]string)
+	for _, desired := range state.Groups {
+		group, err := findGroup(groups, desired)
+		if err != nil {
+			return nil, err
+		}
+		if previous, ok := declared[group.ID]; ok {
+			return nil, fmt.Errorf("group %s is declared twice, as %s and as %s", group.ID, previous, groupKey(desired))
+		}
+		declared[group.ID] = groupKey(desired)
+		plan.groups = append(plan.groups, group)
+
+		if group.IsExternallyManaged() && !allowExternalGroupChanges {
+			plan.skipped[group.ID] = "synced from an external identity provider, enable --allow-external-group-changes to change it"
+			continue
+		}
+
+		desiredRoles := make(map[string]string)
+		desiredUsers := make(map[string]box.User)
+		for _, role := range []string{box.GroupRoleMember, box.GroupRoleAdmin} {
+			keys := desired.Members
+			if role == box.GroupRoleAdmin {
+				keys = desired.Admins
+			}
+
+			for _, key := range keys {
+				user, ok := usersByKey[strings.ToLower(key)]
+				if !ok {
+					return nil, fmt.Errorf("user %s of group %s not found", key, groupKey(desired))
+				}
+				// users listed as members and admins become admins.
+				desiredRoles[user.ID] = role
+				desiredUsers[user.ID] = user
+			}
+		}
+
+		memberships, err := client.GetGroupMemberships(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var changes []membershipChange
+		for _, membership := range memberships {
+			role, ok := desiredRoles[membership.User.ID]
+			switch {
+			case !ok:
+				changes = append(changes, membershipChange{
+					action:       changeRemove,
+					group:        group,
+					user:         membership.User,
+					previousRole: membership.Role,
+					membershipID: membership.ID,
+				})
+			case role != membership.Role:
+				changes = append(changes, membershipChange{
+					action:       changeUpdate,
+					group:        group,
+					user:         membership.User,
+					role:         role,
+					previousRole: membership.Role,
+					membershipID: membership.ID,
+				})
+			}
+			delete(desiredRoles, membership.User.ID)
+		}
+
+		for userID, role := range desiredRoles {
+			changes = append(changes, membershipChange{
+				action: changeAdd,
+				group:  group,
+				user:   desiredUsers[userID],
+				role:   role,
+			})
+		}
+
+		sort.SliceStable(changes, func(i, j int) bool {
+			if changes[i].action != changes[j].action {
+				return changes[i].action < changes[j].action
+			}
+			return changes[i].user.Login < changes[j].user.Login
+		})
+		plan.changes[group.ID] = changes
+	}
+
+	return plan, nil
+}
+
+// findGroup returns the Box group matching the desired group by ID, or by name when the name is unique.
+func findGroup(groups []box.Group, desired desiredGroup) (box.Group, error) {
+	var matches []box.Group
+	for _, group := range groups {
+		if (desired.ID != "" && group.ID == desired.ID) || (desired.ID == "" && group.Name == desired.Name) {
+			matches = append(matches, group)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return box.Group{}, fmt.Errorf("group %s not found, create it with baton-box groups create first", groupKey(desired))
+	case 1:
+		return matches[0], nil
+	default:
+		return box.Group{}, fmt.Errorf("several groups are named %s, select the group by its ID", desired.Name)
+	}
+}
+
+func printPlan(w io.Writer, plan *reconcilePlan) {
+	var added, updated, removed int
+
+	for _, group := range plan.groups {
+		fmt.Fprintf(w, "group %s (%s):\n", group.Name, group.ID)
+
+		if reason, ok := plan.skipped[group.ID]; ok {
+			fmt.Fprintf(w, "  skipped: %s\n", reason)
+			continue
+		}
+
+		changes := plan.changes[group.ID]
+		if len(changes) == 0 {
+			fmt.Fprintln(w, "  no changes")
+			continue
+		}
+
+		for _, change := range changes {
+			switch change.action {
+			case changeAdd:
+				added++
+				fmt.Fprintf(w, "  + add %s as %s\n", change.user.Login, change.role)
+			case changeUpdate:
+				updated++
+				fmt.Fprintf(w, "  ~ change %s from %s to %s\n", change.user.Login, change.previousRole, change.role)
+			case changeRemove:
+				removed++
+				fmt.Fprintf(w, "  - remove %s (%s)\n", change.user.Login, change.previousRole)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "\nPlan: %d to add, %d to change, %d to remove.\n", added, updated, removed)
+}
+
+// applyPlan applies the planned changes group by group and stops at the first failure.
+func applyPlan(ctx context.Context, w io.Writer, client *box.Client, plan *reconcilePlan) error {
+	applied := 0
+
+	for _, group := range plan.groups {
+		for _, change := range plan.changes[group.ID] {
+			var err error
+			switch change.action {
+			case changeAdd:
+				_, err = client.AddGroupMembership(ctx, group.ID, change.user.ID, change.role)
+			case changeUpdate:
+				err = client.UpdateGroupMembership(ctx, change.membershipID, change.role)
+			case changeRemove:
+				err = client.RemoveGroupMembership(ctx, change.membershipID)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to %s %s in group %s after %d applied changes: %w", change.action, change.user.Login, group.Name, applied, err)
+			}
+			applied++
+		}
+	}
+
+	fmt.Fprintf(w, "Applied %d changes.\n", applied)
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-box/pkg/box"
+)
+
+type fakeReconcileClient struct {
+	groups      []box.Group
+	users       []box.User
+	memberships map[string][]box.GroupMembership
+}
+
+func (f *fakeReconcileClient) GetGroups(_ context.Context) ([]box.Group, error) {
+	return f.groups, nil
+}
+
+func (f *fakeReconcileClient) GetUsers(_ context.Context) ([]box.User, error) {
+	return f.users, nil
+}
+
+func (f *fakeReconcileClient) GetGroupMemberships(_ context.Context, groupId string) ([]box.GroupMembership, error) {
+	return f.memberships[groupId], nil
+}
+
+func testUser(id string, login string) box.User {
+	return box.User{BaseType: box.BaseType{ID: id, Type: "user"}, Login: login}
+}
+
+func testMembership(id string, user box.User, role string) box.GroupMembership {
+	return box.GroupMembership{BaseType: box.BaseType{ID: id, Type: "group_membership"}, User: user, Role: role}
+}
+
+// planSummary renders the changes of a group as "action login role" lines for comparison.
+func planSummary(plan *reconcilePlan, groupID string) []string {
+	var rv []string
+	for _, change := range plan.changes[groupID] {
+		switch change.action {
+		case changeRemove:
+			rv = append(rv, change.action+" "+change.user.Login+" "+change.previousRole)
+		default:
+			rv = append(rv, change.action+" "+change.user.Login+" "+change.role)
+		}
+	}
+	return rv
+}
+
+func TestPlanReconcile(t *testing.T) {
+	alice := testUser("1", "alice@example.com")
+	bob := testUser("2", "bob@example.com")
+	carol := testUser("3", "carol@example.com")
+	dave := testUser("4", "dave@example.com")
+
+	engineering := box.Group{BaseType: box.BaseType{ID: "100", Type: "group"}, Name: "Engineering"}
+	synced := box.Group{BaseType: box.BaseType{ID: "200", Type: "group"}, Name: "Synced", Provenance: "okta", ExternalSyncIdentifier: "eng"}
+	labeled := box.Group{BaseType: box.BaseType{ID: "300", Type: "group"}, Name: "Labeled", Provenance: "baton-box"}
+
+	client := &fakeReconcileClient{
+		groups: []box.Group{engineering, synced, labeled},
+		users:  []box.User{alice, bob, carol, dave},
+		memberships: map[string][]box.GroupMembership{
+			"100": {
+				testMembership("m1", alice, box.GroupRoleMember),
+				testMembership("m2", bob, box.GroupRoleAdmin),
+				testMembership("m3", carol, box.GroupRoleMember),
+			},
+			"200": {
+				testMembership("m4", alice, box.GroupRoleMember),
+			},
+			"300": {
+				testMembership("m5", bob, box.GroupRoleMember),
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		state         desiredState
+		allowExternal bool
+		want          []string
+		wantSkipped   bool
+		wantErr       string
+	}{
+		{
+			name: "adds updates and removes",
+			state: desiredState{Groups: []desiredGroup{{
+				Name:    "Engineering",
+				Members: []string{"BOB@example.com", "4"},
+				Admins:  []string{"alice@example.com"},
+			}}},
+			want: []string{
+				"add dave@example.com member",
+				"remove carol@example.com member",
+				"update alice@example.com admin",
+				"update bob@example.com member",
+			},
+		},
+		{
+			name: "admin takes precedence over member",
+			state: desiredState{Groups: []desiredGroup{{
+				ID:      "100",
+				Members: []string{"alice@example.com", "bob@example.com", "carol@example.com", "dave@example.com"},
+				Admins:  []string{"bob@example.com", "dave@example.com"},
+			}}},
+			want: []string{
+				"add dave@example.com admin",
+			},
+		},
+		{
+			name: "no changes",
+			state: desiredState{Groups: []desiredGroup{{
+				Name:    "Engineering",
+				Members: []string{"alice@example.com", "carol@example.com"},
+				Admins:  []string{"bob@example.com"},
+			}}},
+		},
+		{
+			name:        "externally synced group is skipped",
+			state:       desiredState{Groups: []desiredGroup{{Name: "Synced"}}},
+			wantSkipped: true,
+		},
+		{
+			name:          "externally synced group is changed when allowed",
+			state:         desiredState{Groups: []desiredGroup{{Name: "Synced"}}},
+			allowExternal: true,
+			want: []string{
+				"remove alice@example.com member",
+			},
+		},
+		{
+			name:  "group with only a provenance is changed",
+			state: desiredState{Groups: []desiredGroup{{Name: "Labeled"}}},
+			want: []string{
+				"remove bob@example.com member",
+			},
+		},
+		{
+			name:    "unknown user",
+			state:   desiredState{Groups: []desiredGroup{{Name: "Engineering", Members: []string{"eve@example.com"}}}},
+			wantErr: "user eve@example.com of group Engineering not found",
+		},
+		{
+			name:    "group declared by ID and by name",
+			state:   desiredState{Groups: []desiredGroup{{ID: "100"}, {Name: "Engineering"}}},
+			wantErr: "group 100 is declared twice, as 100 and as Engineering",
+		},
+		{
+			name:    "unknown group",
+			state:   desiredState{Groups: []desiredGroup{{Name: "Sales"}}},
+			wantErr: "group Sales not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planReconcile(context.Background(), client, &tt.state, tt.allowExternal)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(plan.groups) != 1 {
+				t.Fatalf("expected 1 planned group, got %d", len(plan.groups))
+			}
+
+			groupID := plan.groups[0].ID
+			if _, skipped := plan.skipped[groupID]; skipped != tt.wantSkipped {
+				t.Fatalf("expected skipped %v, got %v", tt.wantSkipped, skipped)
+			}
+			if got := planSummary(plan, groupID); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unexpected changes:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadDesiredState(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		content    string
+		wantGroups int
+		wantErr    string
+	}{
+		{
+			name:       "yaml",
+			file:       "state.yaml",
+			content:    "groups:\n  - name: Engineering\n    admins: [alice@example.com]\n    members: [bob@example.com]\n",
+			wantGroups: 1,
+		},
+		{
+			name:       "json",
+			file:       "state.json",
+			content:    `{"groups": [{"id": "100", "members": ["bob@example.com"]}, {"name": "Sales"}]}`,
+			wantGroups: 2,
+		},
+		{
+			name:    "empty file",
+			file:    "state.yaml",
+			content: "",
+		},
+		{
+			name:    "unknown key",
+			file:    "state.yaml",
+			content: "groups:\n  - name: Engineering\n    member: [bob@example.com]\n",
+			wantErr: "field member not found",
+		},
+		{
+			name:    "missing name and ID",
+			file:    "state.yaml",
+			content: "groups:\n  - name: Engineering\n  - members: [bob@example.com]\n",
+			wantErr: "group 2 has neither a name nor an ID",
+		},
+		{
+			name:    "duplicate group",
+			file:    "state.yaml",
+			content: "groups:\n  - name: Engineering\n  - name: Engineering\n",
+			wantErr: "group Engineering is declared twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			state, err := readDesiredState(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(state.Groups) != tt.wantGroups {
+				t.Errorf("expected %d groups, got %d", tt.wantGroups, len(state.Groups))
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTransferTimeout          = 6 * time.Hour
+	defaultTransferProgressInterval = 30 * time.Second
+)
+
+// contentTransferrer is the part of the Box client needed to transfer owned content.
+type contentTransferrer interface {
+	TransferOwnedContent(ctx context.Context, fromUserId string, toUserId string, notify bool) (box.Folder, error)
+}
+
+// transferSummary is the result of an owned content transfer.
+type transferSummary struct {
+	FromUserID string `json:"from_user_id"`
+	FromLogin  string `json:"from_login"`
+	ToUserID   string `json:"to_user_id"`
+	ToLogin    string `json:"to_login"`
+	FolderID   string `json:"folder_id"`
+	FolderName string `json:"folder_name"`
+	SpaceUsed  int64  `json:"space_used"`
+	Duration   string `json:"duration"`
+}
+
+func usersTransferContentCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-content",
+		Short: "Move all content owned by a Box user to another user",
+		Long: "Move all content owned by a Box user to another user.\n\n" +
+			"The content is moved into a new folder in the root folder of the receiving user, and a summary with the ID " +
+			"of that folder is printed as JSON. Both users must belong to the enterprise and the receiving user must be " +
+			"active. Box transfers the content in a single request that can take hours for large accounts and reports no " +
+			"progress until it finishes, so the command only logs every --progress-interval that it is still waiting.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			fromKey, _ := flags.GetString("from")
+			toKey, _ := flags.GetString("to")
+			notify, _ := flags.GetBool("notify")
+			timeout, _ := flags.GetDuration("timeout")
+			progressInterval, _ := flags.GetDuration("progress-interval")
+
+			if fromKey == "" || toKey == "" {
+				return fmt.Errorf("from and to are required")
+			}
+			if timeout <= 0 || progressInterval <= 0 {
+				return fmt.Errorf("timeout and progress interval must be positive")
+			}
+
+			ctx, client, cfg, err := setupSubcommand(ctx, cmd)
+			if err != nil {
+				return err
+			}
+
+			from, to, err := preflightTransfer(ctx, client, cfg.EnterpriseID, fromKey, toKey)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			folder, err := transferOwnedContent(ctx, client, from, to, notify, progressInterval)
+			if err != nil {
+				return err
+			}
+
+			return writeJSON(os.Stdout, transferSummary{
+				FromUserID: from.ID,
+				FromLogin:  from.Login,
+				ToUserID:   to.ID,
+				ToLogin:    to.Login,
+				FolderID:   folder.ID,
+				FolderName: folder.Name,
+				SpaceUsed:  from.SpaceUsed,
+				Duration:   time.Since(start).Round(time.Second).String(),
+			})
+		},
+	}
+
+	cmd.Flags().String("from", "", "Login or ID of the user whose content is moved.")
+	cmd.Flags().String("to", "", "Login or ID of the user receiving the content.")
+	cmd.Flags().Bool("notify", false, "Notify the receiving user by email.")
+	cmd.Flags().Duration("timeout", defaultTransferTimeout, "How long to wait for Box to finish the transfer.")
+	cmd.Flags().Duration("progress-interval", defaultTransferProgressInterval, "How often to log that the transfer is still running. Box reports no progress, only the elapsed time is logged.")
+
+	return cmd
+}
+
+// preflightTransfer resolves both users and checks that Box can move the content between them.
+func preflightTransfer(ctx context.Context, client userResolver, enterpriseId string, fromKey string, toKey string) (box.User, box.User, error) {
+	from, err := resolveUser(ctx, client, fromKey)
+	if err != nil {
+		return box.User{}, box.User{}, fmt.Errorf("source user: %w", err)
+	}
+
+	to, err := resolveUser(ctx, client, toKey)
+	if err != nil {
+		return box.User{}, box.User{}, fmt.Errorf("receiving user: %w", err)
+	}
+
+	if from.ID == to.ID {
+		return box.User{}, box.User{}, fmt.Errorf("content cannot be transferred to the same user")
+	}
+	if from.Enterprise.ID != enterpriseId {
+		return box.User{}, box.User{}, fmt.Errorf("source user %s does not belong to enterprise %s", from.Login, enterpriseId)
+	}
+	if to.Enterprise.ID != enterpriseId {
+		return box.User{}, box.User{}, fmt.Errorf("receiving user %s does not belong to enterprise %s", to.Login, enterpriseId)
+	}
+	if to.Status != box.UserStatusActive {
+		return box.User{}, box.User{}, fmt.Errorf("receiving user %s is %s, it must be active", to.Login, to.Status)
+	}
+
+	return from, to, nil
+}
+
+// transferOwnedContent moves the owned content and logs every interval that the transfer is still running.
+// Box reports nothing until the transfer finishes, so the log only carries the elapsed time.
+func transferOwnedContent(
+	ctx context.Context,
+	client contentTransferrer,
+	from box.User,
+	to box.User,
+	notify bool,
+	progressInterval time.Duration,
+) (box.Folder, error) {
+	l := ctxzap.Extract(ctx).With(
+		zap.String("from_user_id", from.ID),
+		zap.String("to_user_id", to.ID),
+		zap.Int64("space_used", from.SpaceUsed),
+	)
+
+	start := time.Now()
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				l.Info("transfer of owned content still running", zap.Duration("elapsed", time.Since(start).Round(time.Second)))
+			}
+		}
+	}()
+
+	l.Info("transferring owned content")
+
+	folder, err := client.TransferOwnedContent(ctx, from.ID, to.ID, notify)
+	if err != nil {
+		return box.Folder{}, err
+	}
+
+	l.Info(
+		"transferred owned content",
+		zap.String("folder_id", folder.ID),
+		zap.Duration("elapsed", time.Since(start).Round(time.Second)),
+	)
+
+	return folder, nil
+}
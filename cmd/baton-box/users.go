@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// usersCmd returns the subcommand that manages Box users.
+func usersCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "users",
+		Short: "Manage Box users",
+	}
+
+	cmd.AddCommand(
+		usersCreateCmd(ctx),
+		usersTransferContentCmd(ctx),
+	)
+
+	return cmd
+}
+
+// groupLister is the part of the Box client needed to look up groups.
+type groupLister interface {
+	GetGroups(ctx context.Context) ([]box.Group, error)
+}
+
+// storagePolicyLister is the part of the Box client needed to look up storage policies.
+type storagePolicyLister interface {
+	GetStoragePolicies(ctx context.Context) ([]box.StoragePolicy, error)
+}
+
+// userCreator is the part of the Box client needed to create a user and roll it back.
+type userCreator interface {
+	CreateUser(ctx context.Context, user box.UserRequest) (box.User, error)
+	DeleteUser(ctx context.Context, userId string, force bool) error
+	AddGroupMembership(ctx context.Context, groupId string, userId string, role string) (box.GroupMembership, error)
+	RemoveGroupMembership(ctx context.Context, membershipId string) error
+	AssignStoragePolicy(ctx context.Context, policyId string, userId string) error
+}
+
+// groupPlacement is a group a new user is added to.
+type groupPlacement struct {
+	group box.Group
+	role  string
+}
+
+func usersCreateCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a managed Box user, add it to groups and assign a storage policy",
+		Long: "Create a managed Box user, add it to groups and assign a storage policy.\n\n" +
+			"Groups and the storage policy are checked before the user is created. When adding the user to a group " +
+			"or assigning the storage policy fails, the user is deleted again so the command can be retried. " +
+			"The created user is printed as JSON.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			login, _ := flags.GetString("login")
+			name, _ := flags.GetString("name")
+			role, _ := flags.GetString("role")
+			status, _ := flags.GetString("status")
+			trackingCodes, _ := flags.GetStringArray("tracking-code")
+			memberOf, _ := flags.GetStringArray("group")
+			adminOf, _ := flags.GetStringArray("group-admin")
+			storagePolicyId, _ := flags.GetString("storage-policy")
+
+			if login == "" || name == "" {
+				return fmt.Errorf("login and name are required")
+			}
+
+			request := box.UserRequest{
+				Login:  login,
+				Name:   name,
+				Role:   role,
+				Status: status,
+			}
+
+			switch role {
+			case box.UserRoleUser, box.UserRoleCoadmin:
+			default:
+				return fmt.Errorf("role must be one of %s or %s", box.UserRoleUser, box.UserRoleCoadmin)
+			}
+
+			switch status {
+			case box.UserStatusActive, box.UserStatusInactive, box.UserStatusCannotDeleteEdit, box.UserStatusCannotDeleteEditUpload:
+			default:
+				return fmt.Errorf(
+					"status must be one of %s, %s, %s or %s",
+					box.UserStatusActive,
+					box.UserStatusInactive,
+					box.UserStatusCannotDeleteEdit,
+					box.UserStatusCannotDeleteEditUpload,
+				)
+			}
+
+			if flags.Changed("space-amount") {
+				spaceAmount, _ := flags.GetInt64("space-amount")
+				request.SpaceAmount = &spaceAmount
+			}
+
+			for _, trackingCode := range trackingCodes {
+				codeName, value, ok := strings.Cut(trackingCode, "=")
+				if !ok || codeName == "" {
+					return fmt.Errorf("tracking code %q must be given as name=value", trackingCode)
+				}
+				request.TrackingCodes = append(request.TrackingCodes, box.TrackingCode{
+					Type:  "tracking_code",
+					Name:  codeName,
+					Value: value,
+				})
+			}
+
+			ctx, client, _, err := setupSubcommand(ctx, cmd)
+			if err != nil {
+				return err
+			}
+
+			placements, err := resolveGroupPlacements(ctx, client, memberOf, adminOf)
+			if err != nil {
+				return err
+			}
+
+			if storagePolicyId != "" {
+				if err := checkStoragePolicy(ctx, client, storagePolicyId); err != nil {
+					return err
+				}
+			}
+
+			user, err := createUser(ctx, client, request, placements, storagePolicyId)
+			if err != nil {
+				return err
+			}
+
+			return writeJSON(os.Stdout, user)
+		},
+	}
+
+	cmd.Flags().String("login", "", "Primary email address of the user.")
+	cmd.Flags().String("name", "", "Name of the user.")
+	cmd.Flags().String("role", box.UserRoleUser, "Role of the user: user or coadmin.")
+	cmd.Flags().Int64("space-amount", -1, "Storage of the user in bytes, -1 for unlimited.")
+	cmd.Flags().String("status", box.UserStatusActive, "Status of the user: active, inactive, cannot_delete_edit or cannot_delete_edit_upload.")
+	cmd.Flags().StringArray("tracking-code", nil, "Tracking code of the user as name=value. Can be repeated.")
+	cmd.Flags().StringArray("group", nil, "Name or ID of a group to add the user to as member. Can be repeated.")
+	cmd.Flags().StringArray("group-admin", nil, "Name or ID of a group to add the user to as admin. Can be repeated.")
+	cmd.Flags().String("storage-policy", "", "ID of the storage policy to assign to the user.")
+
+	return cmd
+}
+
+// resolveGroupPlacements looks up the groups the new user is added to, so unknown groups fail before the user exists.
+// Every group is placed once, as admin when it is given both as member and as admin group.
+func resolveGroupPlacements(ctx context.Context, client groupLister, memberOf []string, adminOf []string) ([]groupPlacement, error) {
+	if len(memberOf) == 0 && len(adminOf) == 0 {
+		return nil, nil
+	}
+
+	groups, err := client.GetGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var placements []groupPlacement
+	placed := make(map[string]int)
+	for _, role := range []string{box.GroupRoleMember, box.GroupRoleAdmin} {
+		keys := memberOf
+		if role == box.GroupRoleAdmin {
+			keys = adminOf
+		}
+
+		for _, key := range keys {
+			desired := desiredGroup{Name: key}
+			if _, err := strconv.ParseUint(key, 10, 64); err == nil {
+				desired = desiredGroup{ID: key}
+			}
+
+			group, err := findGroup(groups, desired)
+			if err != nil {
+				return nil, err
+			}
+			if i, ok := placed[group.ID]; ok {
+				// admin groups come last, so a group given both ways ends up as admin.
+				placements[i].role = role
+				continue
+			}
+			placed[group.ID] = len(placements)
+			placements = append(placements, groupPlacement{group: group, role: role})
+		}
+	}
+
+	return placements, nil
+}
+
+// checkStoragePolicy returns an error when the storage policy does not exist in the enterprise.
+func checkStoragePolicy(ctx context.Context, client storagePolicyLister, storagePolicyId string) error {
+	policies, err := client.GetStoragePolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		if policy.ID == storagePolicyId {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("storage policy %s not found", storagePolicyId)
+}
+
+// createUser creates the user, adds it to the groups and assigns the storage policy.
+// When a group membership or the storage policy fails, the user is deleted again so the command can be retried.
+// If that fails as well, the memberships added so far are removed and the user is left for manual cleanup.
+func createUser(
+	ctx context.Context,
+	client userCreator,
+	request box.UserRequest,
+	placements []groupPlacement,
+	storagePolicyId string,
+) (box.User, error) {
+	l := ctxzap.Extract(ctx)
+
+	user, err := client.CreateUser(ctx, request)
+	if err != nil {
+		return box.User{}, err
+	}
+	l.Info("created user", zap.String("user_id", user.ID), zap.String("login", user.Login))
+
+	var memberships []box.GroupMembership
+	rollback := func(cause error) error {
+		// the user was just created and owns no content, so deleting it also removes its group memberships.
+		err := client.DeleteUser(ctx, user.ID, false)
+		if err == nil {
+			l.Info("deleted user after failure", zap.String("user_id", user.ID))
+			return fmt.Errorf("user %s (%s) was created and deleted again: %w", user.Login, user.ID, cause)
+		}
+		l.Error("failed to delete user after failure", zap.String("user_id", user.ID), zap.Error(err))
+
+		for _, membership := range memberships {
+			if err := client.RemoveGroupMembership(ctx, membership.ID); err != nil {
+				l.Error(
+					"failed to roll back group membership",
+					zap.String("membership_id", membership.ID),
+					zap.String("group_id", membership.Group.ID),
+					zap.Error(err),
+				)
+			}
+		}
+		return fmt.Errorf(
+			"user %s (%s) was created but could not be deleted again (%s), delete it before retrying: %w",
+			user.Login,
+			user.ID,
+			err,
+			cause,
+		)
+	}
+
+	for _, placement := range placements {
+		membership, err := client.AddGroupMembership(ctx, placement.group.ID, user.ID, placement.role)
+		if err != nil {
+			return box.User{}, rollback(fmt.Errorf("group %s: %w", placement.group.Name, err))
+		}
+		memberships = append(memberships, membership)
+		l.Info("added user to group", zap.String("group_id", placement.group.ID), zap.String("role", placement.role))
+	}
+
+	if storagePolicyId != "" {
+		if err := client.AssignStoragePolicy(ctx, storagePolicyId, user.ID); err != nil {
+			return box.User{}, rollback(err)
+		}
+		l.Info("assigned storage policy", zap.String("storage_policy_id", storagePolicyId))
+	}
+
+	return user, nil
+}
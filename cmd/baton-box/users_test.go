@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/conductorone/baton-box/pkg/box"
+)
+
+type fakeGroupLister []box.Group
+
+func (f fakeGroupLister) GetGroups(_ context.Context) ([]box.Group, error) {
+	return f, nil
+}
+
+func TestResolveGroupPlacements(t *testing.T) {
+	groups := fakeGroupLister{
+		{BaseType: box.BaseType{ID: "100", Type: "group"}, Name: "Engineering"},
+		{BaseType: box.BaseType{ID: "200", Type: "group"}, Name: "Sales"},
+	}
+
+	tests := []struct {
+		name     string
+		memberOf []string
+		adminOf  []string
+		want     []string
+	}{
+		{
+			name:     "member and admin groups",
+			memberOf: []string{"Engineering"},
+			adminOf:  []string{"200"},
+			want:     []string{"100 member", "200 admin"},
+		},
+		{
+			name:     "group given as member and admin is placed once as admin",
+			memberOf: []string{"Engineering", "Sales"},
+			adminOf:  []string{"100"},
+			want:     []string{"100 admin", "200 member"},
+		},
+		{
+			name:     "repeated group is placed once",
+			memberOf: []string{"Sales", "200"},
+			want:     []string{"200 member"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placements, err := resolveGroupPlacements(context.Background(), groups, tt.memberOf, tt.adminOf)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, placement := range placements {
+				got = append(got, placement.group.ID+" "+placement.role)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unexpected placements:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeUserCreator struct {
+	calls []string
+	errs  map[string]error
+}
+
+func (f *fakeUserCreator) call(name string) error {
+	f.calls = append(f.calls, name)
+	return f.errs[name]
+}
+
+func (f *fakeUserCreator) CreateUser(_ context.Context, user box.UserRequest) (box.User, error) {
+	if err := f.call("create " + user.Login); err != nil {
+		return box.User{}, err
+	}
+	return box.User{BaseType: box.BaseType{ID: "42", Type: "user"}, Login: user.Login}, nil
+}
+
+func (f *fakeUserCreator) DeleteUser(_ context.Context, userId string, _ bool) error {
+	return f.call("delete " + userId)
+}
+
+func (f *fakeUserCreator) AddGroupMembership(_ context.Context, groupId string, userId string, role string) (box.GroupMembership, error) {
+	if err := f.call("add " + groupId + " " + role); err != nil {
+		return box.GroupMembership{}, err
+	}
+	return box.GroupMembership{BaseType: box.BaseType{ID: "m" + groupId, Type: "group_membership"}}, nil
+}
+
+func (f *fakeUserCreator) RemoveGroupMembership(_ context.Context, membershipId string) error {
+	return f.call("remove " + membershipId)
+}
+
+func (f *fakeUserCreator) AssignStoragePolicy(_ context.Context, policyId string, userId string) error {
+	return f.call("assign " + policyId + " " + userId)
+}
+
+func TestCreateUser(t *testing.T) {
+	placements := []groupPlacement{
+		{group: box.Group{BaseType: box.BaseType{ID: "100", Type: "group"}, Name: "Engineering"}, role: box.GroupRoleMember},
+		{group: box.Group{BaseType: box.BaseType{ID: "200", Type: "group"}, Name: "Sales"}, role: box.GroupRoleAdmin},
+	}
+	failure := errors.New("boom")
+
+	tests := []struct {
+		name      string
+		errs      map[string]error
+		wantErr   bool
+		wantCalls []string
+	}{
+		{
+			name: "user is created, placed and assigned",
+			wantCalls: []string{
+				"create jane@example.com",
+				"add 100 member",
+				"add 200 admin",
+				"assign 7 42",
+			},
+		},
+		{
+			name:    "failed membership deletes the user",
+			errs:    map[string]error{"add 200 admin": failure},
+			wantErr: true,
+			wantCalls: []string{
+				"create jane@example.com",
+				"add 100 member",
+				"add 200 admin",
+				"delete 42",
+			},
+		},
+		{
+			name:    "failed storage policy deletes the user",
+			errs:    map[string]error{"assign 7 42": failure},
+			wantErr: true,
+			wantCalls: []string{
+				"create jane@example.com",
+				"add 100 member",
+				"add 200 admin",
+				"assign 7 42",
+				"delete 42",
+			},
+		},
+		{
+			name:    "memberships are removed when the user cannot be deleted",
+			errs:    map[string]error{"assign 7 42": failure, "delete 42": failure},
+			wantErr: true,
+			wantCalls: []string{
+				"create jane@example.com",
+				"add 100 member",
+				"add 200 admin",
+				"assign 7 42",
+				"delete 42",
+				"remove m100",
+				"remove m200",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeUserCreator{errs: tt.errs}
+			request := box.UserRequest{Login: "jane@example.com", Name: "Jane"}
+
+			_, err := createUser(context.Background(), client, request, placements, "7")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil && !errors.Is(err, failure) {
+				t.Errorf("error does not wrap the cause: %v", err)
+			}
+			if !reflect.DeepEqual(client.calls, tt.wantCalls) {
+				t.Errorf("unexpected calls:\n got: %q\nwant: %q", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -1,22 +1,31 @@
 package box
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 type Client struct {
-	httpClient *http.Client
-	token      string
+	httpClient      *http.Client
+	token           string
+	userType        string
+	extraUserFields []string
+	// asUser is the ID of the user the requests are made on behalf of, see Client.as.
+	asUser string
 }
 
 const (
@@ -24,15 +33,96 @@ const (
 	defaultOffset = 0
 	defaultLimit  = 200
 	errorType     = "error"
+
+	UserTypeAll      = "all"
+	UserTypeManaged  = "managed"
+	UserTypeExternal = "external"
+
+	// UserStatusActive, UserStatusInactive, UserStatusCannotDeleteEdit and UserStatusCannotDeleteEditUpload are
+	// the statuses of a Box user.
+	UserStatusActive                 = "active"
+	UserStatusInactive               = "inactive"
+	UserStatusCannotDeleteEdit       = "cannot_delete_edit"
+	UserStatusCannotDeleteEditUpload = "cannot_delete_edit_upload"
+
+	// UserRoleAdmin, UserRoleCoadmin and UserRoleUser are the enterprise roles of a Box user.
+	UserRoleAdmin   = "admin"
+	UserRoleCoadmin = "coadmin"
+	UserRoleUser    = "user"
+
+	// GroupRoleMember and GroupRoleAdmin are the roles of a Box group membership.
+	GroupRoleMember = "member"
+	GroupRoleAdmin  = "admin"
+
+	// SecurityClassificationTemplateKey is the key of the metadata template holding Box Shield classifications.
+	SecurityClassificationTemplateKey = "securityClassification-6VMVochwUWo"
+	securityClassificationFieldKey    = "Box__Security__Classification__Key"
+
+	// groupFields are the group fields requested from Box.
+	groupFields = "description,external_sync_identifier,invitability_level,member_viewability_level,name,provenance"
+
+	// GroupLevelAdminsOnly, GroupLevelAdminsAndMembers and GroupLevelAllManagedUsers are the values of
+	// the invitability and member viewability levels of a Box group.
+	GroupLevelAdminsOnly       = "admins_only"
+	GroupLevelAdminsAndMembers = "admins_and_members"
+	GroupLevelAllManagedUsers  = "all_managed_users"
+
+	// hubsApiVersion is the Box API version required by the hubs endpoints.
+	hubsApiVersion = "2025.0"
+
+	// StreamTypeAdminLogs returns enterprise events for a time range in the order they happened.
+	StreamTypeAdminLogs = "admin_logs"
+	// StreamTypeAdminLogsStreaming returns enterprise events of the last two weeks as a live stream.
+	StreamTypeAdminLogsStreaming = "admin_logs_streaming"
+	// StreamPositionNow is the stream position of the latest enterprise event.
+	StreamPositionNow = "now"
+	// userEnterpriseField is only requested for single users, listing the users of the enterprise does not need it.
+	userEnterpriseField = "enterprise"
+
+	// maxEventsLimit is the maximum number of events returned by a single events request.
+	maxEventsLimit = 500
+
+	// CollaborationStatusPending is the status of collaborations the invited user has not accepted yet.
+	CollaborationStatusPending  = "pending"
+	collaborationStatusRejected = "rejected"
+
+	// itemFields are the fields of folder items requested from Box.
+	itemFields = "name,owned_by"
 )
 
+// defaultUserFields are the user fields requested from Box by default.
+var defaultUserFields = []string{
+	"address",
+	"created_at",
+	"is_exempt_from_device_limits",
+	"is_exempt_from_login_verification",
+	"is_external_collab_restricted",
+	"is_platform_access_only",
+	"is_sync_enabled",
+	"job_title",
+	"language",
+	"login",
+	"max_upload_size",
+	"modified_at",
+	"name",
+	"notification_email",
+	"phone",
+	"role",
+	"space_amount",
+	"space_used",
+	"status",
+	"timezone",
+	"tracking_codes",
+}
+
 type paginationData struct {
 	Limit      int `json:"limit"`
 	Offset     int `json:"offset"`
 	TotalCount int `json:"total_count"`
 }
 
-var ErrorResponse struct {
+// ErrorResponse is the error body returned by the Box API.
+type ErrorResponse struct {
 	Type        string `json:"type"`
 	Code        string `json:"code"`
 	ContextInfo struct {
@@ -44,11 +134,83 @@ var ErrorResponse struct {
 	Status    int64  `json:"status"`
 }
 
-func NewClient(httpClient *http.Client, token string) *Client {
+func (e *ErrorResponse) Error() string {
+	return e.Message
+}
+
+// NewClient creates a Box API client. Extra user fields are requested on top of the default user fields.
+// With an empty token the HTTP client is expected to authenticate the requests, e.g. through an oauth2 transport.
+func NewClient(httpClient *http.Client, token string, userType string, extraUserFields []string) *Client {
 	return &Client{
-		httpClient: httpClient,
-		token:      token,
+		httpClient:      httpClient,
+		token:           token,
+		userType:        userType,
+		extraUserFields: extraUserFields,
+	}
+}
+
+// as returns a copy of the client that makes requests on behalf of the given user.
+// It requires an application allowed to perform actions as users.
+func (c *Client) as(userId string) *Client {
+	asClient := *c
+	asClient.asUser = userId
+	return &asClient
+}
+
+// returns the comma separated list of user fields to request, with the given fields on top of the configured ones.
+func (c *Client) userFieldsQuery(fields ...string) string {
+	fields = append(append([]string{}, defaultUserFields...), fields...)
+	for _, field := range c.extraUserFields {
+		if !contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// sets the values of the configured extra fields on the user.
+func (c *Client) setExtraFields(user *User) {
+	for _, field := range c.extraUserFields {
+		if contains(defaultUserFields, field) {
+			continue
+		}
+
+		value, ok := user.raw[field]
+		if !ok {
+			continue
+		}
+
+		if user.ExtraFields == nil {
+			user.ExtraFields = make(map[string]interface{})
+		}
+		user.ExtraFields[field] = value
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+type markerPaginationData struct {
+	Limit      int    `json:"limit"`
+	NextMarker string `json:"next_marker"`
+}
+
+// returns query params with marker based pagination options.
+func markerPaginationQuery(marker string, limit int) url.Values {
+	q := url.Values{}
+	q.Add("limit", strconv.Itoa(limit))
+	if marker != "" {
+		q.Add("marker", marker)
 	}
+	return q
 }
 
 // returns query params with pagination options.
@@ -108,8 +270,39 @@ func RequestAccessToken(ctx context.Context, clientID string, clientSecret strin
 	return res.AccessToken, nil
 }
 
+// NewTokenSource returns a token source that requests a new access token whenever the current one expires,
+// for commands that run longer than the lifetime of a single token.
+func NewTokenSource(ctx context.Context, clientID string, clientSecret string, enterpriseId string) (oauth2.TokenSource, error) {
+	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     fmt.Sprint(baseUrl, "/oauth2/token"),
+		EndpointParams: url.Values{
+			"box_subject_type": {"enterprise"},
+			"box_subject_id":   {enterpriseId},
+		},
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+
+	return cfg.TokenSource(context.WithValue(ctx, oauth2.HTTPClient, httpClient)), nil
+}
+
 // GetUsers returns all users from Box enterprise.
 func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
+	return c.getUsers(ctx, c.userType)
+}
+
+// GetManagedUsers returns the managed users of the Box enterprise, whatever user type the client lists.
+func (c *Client) GetManagedUsers(ctx context.Context) ([]User, error) {
+	return c.getUsers(ctx, UserTypeManaged)
+}
+
+func (c *Client) getUsers(ctx context.Context, userType string) ([]User, error) {
 	var allUsers []User
 	offset := defaultOffset
 	totalReturned := 0
@@ -122,15 +315,18 @@ func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
 
 	for {
 		q := paginationQuery(offset, defaultLimit)
-		q.Set("fields", "role,name,login,status")
+		q.Set("fields", c.userFieldsQuery())
+		if userType != "" {
+			q.Set("user_type", userType)
+		}
 
 		if err := c.doRequest(ctx, usersUrl, &res, q); err != nil {
-			if ErrorResponse.Type == errorType {
-				return nil, fmt.Errorf("failed to get users: %s", ErrorResponse.Message)
-			}
-			return nil, err
+			return nil, fmt.Errorf("failed to get users: %w", err)
 		}
 
+		for i := range res.Users {
+			c.setExtraFields(&res.Users[i])
+		}
 		allUsers = append(allUsers, res.Users...)
 
 		totalReturned += res.Limit
@@ -144,6 +340,266 @@ func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
 	return allUsers, nil
 }
 
+// GetUser returns a Box user by ID.
+func (c *Client) GetUser(ctx context.Context, userId string) (User, error) {
+	userUrl := fmt.Sprint(baseUrl, "/2.0/users/", userId)
+
+	var res User
+	params := url.Values{}
+	params.Set("fields", c.userFieldsQuery(userEnterpriseField))
+
+	if err := c.doRequest(ctx, userUrl, &res, params); err != nil {
+		return User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	c.setExtraFields(&res)
+
+	return res, nil
+}
+
+// GetUserByLogin returns the Box user with the given login, of any user type.
+func (c *Client) GetUserByLogin(ctx context.Context, login string) (User, error) {
+	usersUrl := fmt.Sprint(baseUrl, "/2.0/users")
+
+	var res struct {
+		paginationData
+		Users []User `json:"entries"`
+	}
+
+	// filter_term matches the beginning of logins and names, so the exact login is looked up in the results.
+	q := paginationQuery(defaultOffset, defaultLimit)
+	q.Set("fields", c.userFieldsQuery(userEnterpriseField))
+	q.Set("filter_term", login)
+	q.Set("user_type", UserTypeAll)
+
+	if err := c.doRequest(ctx, usersUrl, &res, q); err != nil {
+		return User{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	for _, user := range res.Users {
+		if strings.EqualFold(user.Login, login) {
+			c.setExtraFields(&user)
+			return user, nil
+		}
+	}
+
+	return User{}, fmt.Errorf("user with login %s not found", login)
+}
+
+// GetUserGroupMemberships returns all group memberships of a Box user.
+func (c *Client) GetUserGroupMemberships(ctx context.Context, userId string) ([]GroupMembership, error) {
+	var allGroupMemberships []GroupMembership
+	offset := defaultOffset
+	totalReturned := 0
+	membershipsUrl := fmt.Sprintf("%s/2.0/users/%s/memberships", baseUrl, userId)
+
+	var res struct {
+		paginationData
+		GroupMembership []GroupMembership `json:"entries"`
+	}
+
+	for {
+		q := paginationQuery(offset, defaultLimit)
+		if err := c.doRequest(ctx, membershipsUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get user group memberships: %w", err)
+		}
+
+		allGroupMemberships = append(allGroupMemberships, res.GroupMembership...)
+
+		totalReturned += res.Limit
+		if totalReturned >= res.TotalCount {
+			break
+		}
+
+		offset += res.Limit
+	}
+
+	return allGroupMemberships, nil
+}
+
+// CreateUser creates a managed Box user.
+func (c *Client) CreateUser(ctx context.Context, user UserRequest) (User, error) {
+	usersUrl := fmt.Sprint(baseUrl, "/2.0/users")
+
+	var res User
+	params := url.Values{}
+	params.Set("fields", c.userFieldsQuery(userEnterpriseField))
+
+	if err := c.makeRequest(ctx, http.MethodPost, usersUrl, params, user, &res, ""); err != nil {
+		return User{}, fmt.Errorf("failed to create user: %w", err)
+	}
+	c.setExtraFields(&res)
+
+	return res, nil
+}
+
+// SetUserStatus changes the status of a Box user, e.g. to inactive to prevent the user from signing in.
+func (c *Client) SetUserStatus(ctx context.Context, userId string, status string) error {
+	userUrl := fmt.Sprint(baseUrl, "/2.0/users/", userId)
+
+	body := map[string]interface{}{
+		"status": status,
+	}
+
+	if err := c.doRequestWithBody(ctx, http.MethodPut, userUrl, body, nil); err != nil {
+		return fmt.Errorf("failed to update user status: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUser deletes a Box user. Without force Box refuses to delete users who still own content.
+func (c *Client) DeleteUser(ctx context.Context, userId string, force bool) error {
+	userUrl := fmt.Sprint(baseUrl, "/2.0/users/", userId)
+
+	params := url.Values{}
+	params.Set("force", strconv.FormatBool(force))
+
+	if err := c.makeRequest(ctx, http.MethodDelete, userUrl, params, nil, nil, ""); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+// TransferOwnedContent moves all content owned by a Box user into a new folder in the root folder of another user.
+// It returns the new folder. Box performs the transfer synchronously, which can take a long time for large accounts.
+func (c *Client) TransferOwnedContent(ctx context.Context, fromUserId string, toUserId string, notify bool) (Folder, error) {
+	transferUrl := fmt.Sprintf("%s/2.0/users/%s/folders/0", baseUrl, fromUserId)
+
+	body := map[string]interface{}{
+		"owned_by": BaseType{ID: toUserId, Type: "user"},
+	}
+
+	params := url.Values{}
+	params.Set("notify", strconv.FormatBool(notify))
+
+	var res Folder
+	if err := c.makeRequest(ctx, http.MethodPut, transferUrl, params, body, &res, ""); err != nil {
+		return Folder{}, fmt.Errorf("failed to transfer owned content: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetFolderItemsAsUser returns all items of a folder as seen by the given user. The root folder has ID 0.
+func (c *Client) GetFolderItemsAsUser(ctx context.Context, userId string, folderId string) ([]Item, error) {
+	var allItems []Item
+	marker := ""
+	itemsUrl := fmt.Sprintf("%s/2.0/folders/%s/items", baseUrl, folderId)
+
+	for {
+		var res struct {
+			markerPaginationData
+			Items []Item `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		q.Set("usemarker", "true")
+		q.Set("fields", itemFields)
+		if err := c.as(userId).doRequest(ctx, itemsUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get folder items: %w", err)
+		}
+
+		allItems = append(allItems, res.Items...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allItems, nil
+}
+
+// GetItemCollaborationsAsUser returns all collaborations of a file or folder as seen by the given user.
+func (c *Client) GetItemCollaborationsAsUser(ctx context.Context, userId string, item Item) ([]Collaboration, error) {
+	var allCollaborations []Collaboration
+	marker := ""
+	collaborationsUrl := fmt.Sprintf("%s/2.0/%ss/%s/collaborations", baseUrl, item.Type, item.ID)
+
+	for {
+		var res struct {
+			markerPaginationData
+			Collaborations []Collaboration `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		if err := c.as(userId).doRequest(ctx, collaborationsUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get %s collaborations: %w", item.Type, err)
+		}
+
+		allCollaborations = append(allCollaborations, res.Collaborations...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allCollaborations, nil
+}
+
+// GetPendingCollaborationsAsUser returns all collaborations the given user is invited to and has not accepted yet.
+func (c *Client) GetPendingCollaborationsAsUser(ctx context.Context, userId string) ([]Collaboration, error) {
+	var allCollaborations []Collaboration
+	offset := defaultOffset
+	totalReturned := 0
+	collaborationsUrl := fmt.Sprint(baseUrl, "/2.0/collaborations")
+
+	var res struct {
+		paginationData
+		Collaborations []Collaboration `json:"entries"`
+	}
+
+	for {
+		q := paginationQuery(offset, defaultLimit)
+		q.Set("status", CollaborationStatusPending)
+
+		if err := c.as(userId).doRequest(ctx, collaborationsUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get pending collaborations: %w", err)
+		}
+
+		allCollaborations = append(allCollaborations, res.Collaborations...)
+
+		totalReturned += res.Limit
+		if totalReturned >= res.TotalCount {
+			break
+		}
+
+		offset += res.Limit
+	}
+
+	return allCollaborations, nil
+}
+
+// DeleteCollaborationAsUser removes the given user from a collaboration they accepted.
+func (c *Client) DeleteCollaborationAsUser(ctx context.Context, userId string, collaborationId string) error {
+	collaborationUrl := fmt.Sprint(baseUrl, "/2.0/collaborations/", collaborationId)
+
+	if err := c.as(userId).doRequestWithBody(ctx, http.MethodDelete, collaborationUrl, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete collaboration: %w", err)
+	}
+
+	return nil
+}
+
+// RejectCollaborationAsUser declines a pending collaboration on behalf of the invited user.
+func (c *Client) RejectCollaborationAsUser(ctx context.Context, userId string, collaborationId string) error {
+	collaborationUrl := fmt.Sprint(baseUrl, "/2.0/collaborations/", collaborationId)
+
+	body := map[string]interface{}{
+		"status": collaborationStatusRejected,
+	}
+
+	if err := c.as(userId).doRequestWithBody(ctx, http.MethodPut, collaborationUrl, body, nil); err != nil {
+		return fmt.Errorf("failed to reject collaboration: %w", err)
+	}
+
+	return nil
+}
+
 // GetGroups returns all groups from Box enterprise.
 func (c *Client) GetGroups(ctx context.Context) ([]Group, error) {
 	var allGroups []Group
@@ -158,13 +614,10 @@ func (c *Client) GetGroups(ctx context.Context) ([]Group, error) {
 
 	for {
 		q := paginationQuery(offset, defaultLimit)
-		q.Set("fields", "invitability_level,member_viewability_level,name")
+		q.Set("fields", groupFields)
 
 		if err := c.doRequest(ctx, usersUrl, &res, q); err != nil {
-			if ErrorResponse.Type == errorType {
-				return nil, fmt.Errorf("failed to get groups: %s", ErrorResponse.Message)
-			}
-			return nil, err
+			return nil, fmt.Errorf("failed to get groups: %w", err)
 		}
 
 		allGroups = append(allGroups, res.Groups...)
@@ -195,10 +648,7 @@ func (c *Client) GetGroupMemberships(ctx context.Context, groupId string) ([]Gro
 	for {
 		q := paginationQuery(offset, defaultLimit)
 		if err := c.doRequest(ctx, usersUrl, &res, q); err != nil {
-			if ErrorResponse.Type == errorType {
-				return nil, fmt.Errorf("failed to get group memberships: %s", ErrorResponse.Message)
-			}
-			return nil, err
+			return nil, fmt.Errorf("failed to get group memberships: %w", err)
 		}
 
 		allGroupMemberships = append(allGroupMemberships, res.GroupMembership...)
@@ -222,10 +672,7 @@ func (c *Client) GetCurrentUserWithEnterprise(ctx context.Context) (User, error)
 
 	var res User
 	if err := c.doRequest(ctx, usersUrl, &res, params); err != nil {
-		if ErrorResponse.Type == errorType {
-			return User{}, fmt.Errorf("failed to get current user: %s", ErrorResponse.Message)
-		}
-		return User{}, err
+		return User{}, fmt.Errorf("failed to get current user: %w", err)
 	}
 
 	return res, nil
@@ -237,48 +684,889 @@ func (c *Client) GetGroup(ctx context.Context, groupId string) (Group, error) {
 
 	var res Group
 	params := url.Values{}
-	params.Set("fields", "invitability_level,member_viewability_level,name")
+	params.Set("fields", groupFields)
 
 	if err := c.doRequest(ctx, usersUrl, &res, params); err != nil {
-		if ErrorResponse.Type == errorType {
-			return Group{}, fmt.Errorf("failed to get group: %s", ErrorResponse.Message)
-		}
-		return Group{}, err
+		return Group{}, fmt.Errorf("failed to get group: %w", err)
 	}
 
 	return res, nil
 }
 
-func (c *Client) doRequest(ctx context.Context, url string, res interface{}, params url.Values) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return err
+// CreateGroup creates a Box group.
+func (c *Client) CreateGroup(ctx context.Context, group GroupRequest) (Group, error) {
+	groupsUrl := fmt.Sprint(baseUrl, "/2.0/groups")
+
+	var res Group
+	params := url.Values{}
+	params.Set("fields", groupFields)
+
+	if err := c.makeRequest(ctx, http.MethodPost, groupsUrl, params, group, &res, ""); err != nil {
+		return Group{}, fmt.Errorf("failed to create group: %w", err)
 	}
 
-	if params != nil {
-		req.URL.RawQuery = params.Encode()
+	return res, nil
+}
+
+// UpdateGroup changes the fields of a Box group set in the request.
+func (c *Client) UpdateGroup(ctx context.Context, groupId string, group GroupRequest) (Group, error) {
+	groupUrl := fmt.Sprint(baseUrl, "/2.0/groups/", groupId)
+
+	var res Group
+	params := url.Values{}
+	params.Set("fields", groupFields)
+
+	if err := c.makeRequest(ctx, http.MethodPut, groupUrl, params, group, &res, ""); err != nil {
+		return Group{}, fmt.Errorf("failed to update group: %w", err)
 	}
 
-	req.Header.Add("accept", "application/json")
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	return res, nil
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return err
+// DeleteGroup deletes a Box group and all of its memberships.
+func (c *Client) DeleteGroup(ctx context.Context, groupId string) error {
+	groupUrl := fmt.Sprint(baseUrl, "/2.0/groups/", groupId)
+
+	if err := c.doRequestWithBody(ctx, http.MethodDelete, groupUrl, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
 	}
 
-	defer resp.Body.Close()
+	return nil
+}
 
-	// all GET requests in Box API return 200 status code if sucessful.
-	if resp.StatusCode != http.StatusOK {
-		if err := json.NewDecoder(resp.Body).Decode(&ErrorResponse); err != nil {
-			return err
-		}
+// AddGroupMembership adds a user to a Box group with the given role and returns the new membership.
+func (c *Client) AddGroupMembership(ctx context.Context, groupId string, userId string, role string) (GroupMembership, error) {
+	membershipsUrl := fmt.Sprint(baseUrl, "/2.0/group_memberships")
+
+	body := map[string]interface{}{
+		"group": BaseType{ID: groupId, Type: "group"},
+		"user":  BaseType{ID: userId, Type: "user"},
+		"role":  role,
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return err
+	var res GroupMembership
+	if err := c.doRequestWithBody(ctx, http.MethodPost, membershipsUrl, body, &res); err != nil {
+		return GroupMembership{}, fmt.Errorf("failed to add group membership: %w", err)
+	}
+
+	return res, nil
+}
+
+// UpdateGroupMembership changes the role of a Box group membership.
+func (c *Client) UpdateGroupMembership(ctx context.Context, membershipId string, role string) error {
+	membershipUrl := fmt.Sprint(baseUrl, "/2.0/group_memberships/", membershipId)
+
+	body := map[string]interface{}{
+		"role": role,
+	}
+
+	if err := c.doRequestWithBody(ctx, http.MethodPut, membershipUrl, body, nil); err != nil {
+		return fmt.Errorf("failed to update group membership: %w", err)
 	}
 
 	return nil
 }
+
+// RemoveGroupMembership removes a user from a Box group.
+func (c *Client) RemoveGroupMembership(ctx context.Context, membershipId string) error {
+	membershipUrl := fmt.Sprint(baseUrl, "/2.0/group_memberships/", membershipId)
+
+	if err := c.doRequestWithBody(ctx, http.MethodDelete, membershipUrl, nil, nil); err != nil {
+		return fmt.Errorf("failed to remove group membership: %w", err)
+	}
+
+	return nil
+}
+
+// GetEmailAliases returns all email aliases of a Box user.
+func (c *Client) GetEmailAliases(ctx context.Context, userId string) ([]EmailAlias, error) {
+	aliasesUrl := fmt.Sprintf("%s/2.0/users/%s/email_aliases", baseUrl, userId)
+
+	var res struct {
+		TotalCount   int          `json:"total_count"`
+		EmailAliases []EmailAlias `json:"entries"`
+	}
+
+	if err := c.doRequest(ctx, aliasesUrl, &res, nil); err != nil {
+		return nil, fmt.Errorf("failed to get email aliases: %w", err)
+	}
+
+	return res.EmailAliases, nil
+}
+
+// DeleteEmailAlias removes an email alias from a Box user.
+func (c *Client) DeleteEmailAlias(ctx context.Context, userId string, aliasId string) error {
+	aliasUrl := fmt.Sprintf("%s/2.0/users/%s/email_aliases/%s", baseUrl, userId, aliasId)
+
+	if err := c.doRequestWithBody(ctx, http.MethodDelete, aliasUrl, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete email alias: %w", err)
+	}
+
+	return nil
+}
+
+// GetLegalHoldPolicies returns all legal hold policies from Box enterprise.
+func (c *Client) GetLegalHoldPolicies(ctx context.Context) ([]LegalHoldPolicy, error) {
+	var allPolicies []LegalHoldPolicy
+	marker := ""
+	policiesUrl := fmt.Sprint(baseUrl, "/2.0/legal_hold_policies")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Policies []LegalHoldPolicy `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		if err := c.doRequest(ctx, policiesUrl, &res, q); err != nil {
+			// enterprises without Box Governance have no legal hold policies.
+			if isFeatureUnavailable(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get legal hold policies: %w", err)
+		}
+
+		allPolicies = append(allPolicies, res.Policies...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allPolicies, nil
+}
+
+// GetLegalHoldPolicyAssignments returns all assignments of a legal hold policy.
+func (c *Client) GetLegalHoldPolicyAssignments(ctx context.Context, policyId string) ([]LegalHoldPolicyAssignment, error) {
+	var allAssignments []LegalHoldPolicyAssignment
+	marker := ""
+	assignmentsUrl := fmt.Sprint(baseUrl, "/2.0/legal_hold_policy_assignments")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Assignments []LegalHoldPolicyAssignment `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		q.Set("policy_id", policyId)
+		q.Set("fields", "assigned_to,assigned_at,assigned_by")
+		if err := c.doRequest(ctx, assignmentsUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get legal hold policy assignments: %w", err)
+		}
+
+		allAssignments = append(allAssignments, res.Assignments...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allAssignments, nil
+}
+
+// GetRetentionPolicies returns all retention policies from Box enterprise.
+func (c *Client) GetRetentionPolicies(ctx context.Context) ([]RetentionPolicy, error) {
+	var allPolicies []RetentionPolicy
+	marker := ""
+	policiesUrl := fmt.Sprint(baseUrl, "/2.0/retention_policies")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Policies []RetentionPolicy `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		q.Set("fields", "policy_name,description,policy_type,retention_type,retention_length,disposition_action,status,"+
+			"can_owner_extend_retention,are_owners_notified,assignment_counts,created_by,created_at,modified_at")
+		if err := c.doRequest(ctx, policiesUrl, &res, q); err != nil {
+			// enterprises without Box Governance have no retention policies.
+			if isFeatureUnavailable(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get retention policies: %w", err)
+		}
+
+		allPolicies = append(allPolicies, res.Policies...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allPolicies, nil
+}
+
+// GetRetentionPolicyAssignments returns all assignments of a retention policy.
+func (c *Client) GetRetentionPolicyAssignments(ctx context.Context, policyId string) ([]RetentionPolicyAssignment, error) {
+	var allAssignments []RetentionPolicyAssignment
+	marker := ""
+	assignmentsUrl := fmt.Sprintf("%s/2.0/retention_policies/%s/assignments", baseUrl, policyId)
+
+	for {
+		var res struct {
+			markerPaginationData
+			Assignments []RetentionPolicyAssignment `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		q.Set("fields", "assigned_to,assigned_at,assigned_by,start_date_field")
+		if err := c.doRequest(ctx, assignmentsUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get retention policy assignments: %w", err)
+		}
+
+		allAssignments = append(allAssignments, res.Assignments...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allAssignments, nil
+}
+
+// GetSecurityClassifications returns the security classification labels defined in Box enterprise.
+func (c *Client) GetSecurityClassifications(ctx context.Context) ([]SecurityClassification, error) {
+	schemaUrl := fmt.Sprintf("%s/2.0/metadata_templates/enterprise/%s/schema", baseUrl, SecurityClassificationTemplateKey)
+
+	var res MetadataTemplate
+	if err := c.doRequest(ctx, schemaUrl, &res, nil); err != nil {
+		var errorResponse *ErrorResponse
+		// enterprises without Box Shield have no classification template.
+		if errors.As(err, &errorResponse) && errorResponse.Status == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get security classifications: %w", err)
+	}
+
+	var classifications []SecurityClassification
+	for _, field := range res.Fields {
+		if field.Key != securityClassificationFieldKey {
+			continue
+		}
+		classifications = append(classifications, field.Options...)
+	}
+
+	return classifications, nil
+}
+
+// GetShieldInformationBarriers returns all Box Shield information barriers from Box enterprise.
+func (c *Client) GetShieldInformationBarriers(ctx context.Context) ([]ShieldInformationBarrier, error) {
+	var allBarriers []ShieldInformationBarrier
+	marker := ""
+	barriersUrl := fmt.Sprint(baseUrl, "/2.0/shield_information_barriers")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Barriers []ShieldInformationBarrier `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		if err := c.doRequest(ctx, barriersUrl, &res, q); err != nil {
+			// enterprises without Box Shield have no information barriers.
+			if isFeatureUnavailable(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get shield information barriers: %w", err)
+		}
+
+		allBarriers = append(allBarriers, res.Barriers...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allBarriers, nil
+}
+
+// GetShieldInformationBarrierSegments returns all segments of a Box Shield information barrier.
+func (c *Client) GetShieldInformationBarrierSegments(ctx context.Context, barrierId string) ([]ShieldInformationBarrierSegment, error) {
+	var allSegments []ShieldInformationBarrierSegment
+	marker := ""
+	segmentsUrl := fmt.Sprint(baseUrl, "/2.0/shield_information_barrier_segments")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Segments []ShieldInformationBarrierSegment `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		q.Set("shield_information_barrier_id", barrierId)
+		if err := c.doRequest(ctx, segmentsUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get shield information barrier segments: %w", err)
+		}
+
+		allSegments = append(allSegments, res.Segments...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allSegments, nil
+}
+
+// GetShieldInformationBarrierSegmentMembers returns all members of a Box Shield information barrier segment.
+func (c *Client) GetShieldInformationBarrierSegmentMembers(ctx context.Context, segmentId string) ([]ShieldInformationBarrierSegmentMember, error) {
+	var allMembers []ShieldInformationBarrierSegmentMember
+	marker := ""
+	membersUrl := fmt.Sprint(baseUrl, "/2.0/shield_information_barrier_segment_members")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Members []ShieldInformationBarrierSegmentMember `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		q.Set("shield_information_barrier_segment_id", segmentId)
+		if err := c.doRequest(ctx, membersUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get shield information barrier segment members: %w", err)
+		}
+
+		allMembers = append(allMembers, res.Members...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allMembers, nil
+}
+
+// GetShieldInformationBarrierSegmentRestrictions returns all restrictions of a Box Shield information barrier segment.
+func (c *Client) GetShieldInformationBarrierSegmentRestrictions(ctx context.Context, segmentId string) ([]ShieldInformationBarrierSegmentRestriction, error) {
+	var allRestrictions []ShieldInformationBarrierSegmentRestriction
+	marker := ""
+	restrictionsUrl := fmt.Sprint(baseUrl, "/2.0/shield_information_barrier_segment_restrictions")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Restrictions []ShieldInformationBarrierSegmentRestriction `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		q.Set("shield_information_barrier_segment_id", segmentId)
+		if err := c.doRequest(ctx, restrictionsUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get shield information barrier segment restrictions: %w", err)
+		}
+
+		allRestrictions = append(allRestrictions, res.Restrictions...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allRestrictions, nil
+}
+
+// AddShieldInformationBarrierSegmentMember adds a user to a Box Shield information barrier segment.
+func (c *Client) AddShieldInformationBarrierSegmentMember(ctx context.Context, segmentId string, userId string) error {
+	membersUrl := fmt.Sprint(baseUrl, "/2.0/shield_information_barrier_segment_members")
+
+	body := map[string]interface{}{
+		"shield_information_barrier_segment": BaseType{ID: segmentId, Type: "shield_information_barrier_segment"},
+		"user":                               BaseType{ID: userId, Type: "user"},
+	}
+
+	if err := c.doRequestWithBody(ctx, http.MethodPost, membersUrl, body, nil); err != nil {
+		return fmt.Errorf("failed to add shield information barrier segment member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveShieldInformationBarrierSegmentMember removes a member from a Box Shield information barrier segment.
+func (c *Client) RemoveShieldInformationBarrierSegmentMember(ctx context.Context, memberId string) error {
+	memberUrl := fmt.Sprint(baseUrl, "/2.0/shield_information_barrier_segment_members/", memberId)
+
+	if err := c.doRequestWithBody(ctx, http.MethodDelete, memberUrl, nil, nil); err != nil {
+		return fmt.Errorf("failed to remove shield information barrier segment member: %w", err)
+	}
+
+	return nil
+}
+
+// GetStoragePolicies returns all storage policies available in Box enterprise.
+func (c *Client) GetStoragePolicies(ctx context.Context) ([]StoragePolicy, error) {
+	var allPolicies []StoragePolicy
+	marker := ""
+	policiesUrl := fmt.Sprint(baseUrl, "/2.0/storage_policies")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Policies []StoragePolicy `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		if err := c.doRequest(ctx, policiesUrl, &res, q); err != nil {
+			// enterprises without Box Zones have no storage policies.
+			if isFeatureUnavailable(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get storage policies: %w", err)
+		}
+
+		allPolicies = append(allPolicies, res.Policies...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allPolicies, nil
+}
+
+// GetUserStoragePolicyAssignment returns the storage policy assignment in effect for a Box user.
+// Users without their own assignment resolve to the assignment of the enterprise.
+func (c *Client) GetUserStoragePolicyAssignment(ctx context.Context, userId string) (StoragePolicyAssignment, error) {
+	assignmentsUrl := fmt.Sprint(baseUrl, "/2.0/storage_policy_assignments")
+
+	var res struct {
+		Assignments []StoragePolicyAssignment `json:"entries"`
+	}
+
+	q := url.Values{}
+	q.Set("resolved_for_type", "user")
+	q.Set("resolved_for_id", userId)
+	if err := c.doRequest(ctx, assignmentsUrl, &res, q); err != nil {
+		return StoragePolicyAssignment{}, fmt.Errorf("failed to get storage policy assignment: %w", err)
+	}
+
+	if len(res.Assignments) == 0 {
+		return StoragePolicyAssignment{}, fmt.Errorf("failed to get storage policy assignment: no assignment found for user %s", userId)
+	}
+
+	return res.Assignments[0], nil
+}
+
+// AssignStoragePolicy assigns a storage policy to a Box user, replacing the user's own assignment if there is one.
+func (c *Client) AssignStoragePolicy(ctx context.Context, policyId string, userId string) error {
+	assignment, err := c.GetUserStoragePolicyAssignment(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	storagePolicy := BaseType{ID: policyId, Type: "storage_policy"}
+
+	if assignment.AssignedTo.Type == "user" {
+		assignmentUrl := fmt.Sprint(baseUrl, "/2.0/storage_policy_assignments/", assignment.ID)
+		body := map[string]interface{}{
+			"storage_policy": storagePolicy,
+		}
+
+		if err := c.doRequestWithBody(ctx, http.MethodPut, assignmentUrl, body, nil); err != nil {
+			return fmt.Errorf("failed to update storage policy assignment: %w", err)
+		}
+		return nil
+	}
+
+	assignmentsUrl := fmt.Sprint(baseUrl, "/2.0/storage_policy_assignments")
+	body := map[string]interface{}{
+		"storage_policy": storagePolicy,
+		"assigned_to":    BaseType{ID: userId, Type: "user"},
+	}
+
+	if err := c.doRequestWithBody(ctx, http.MethodPost, assignmentsUrl, body, nil); err != nil {
+		return fmt.Errorf("failed to create storage policy assignment: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStoragePolicyAssignment deletes a storage policy assignment, users fall back to the enterprise storage policy.
+func (c *Client) DeleteStoragePolicyAssignment(ctx context.Context, assignmentId string) error {
+	assignmentUrl := fmt.Sprint(baseUrl, "/2.0/storage_policy_assignments/", assignmentId)
+
+	if err := c.doRequestWithBody(ctx, http.MethodDelete, assignmentUrl, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete storage policy assignment: %w", err)
+	}
+
+	return nil
+}
+
+// GetCollaborationAllowlistEntries returns all domains on the collaboration allowlist of Box enterprise.
+func (c *Client) GetCollaborationAllowlistEntries(ctx context.Context) ([]CollaborationAllowlistEntry, error) {
+	var allEntries []CollaborationAllowlistEntry
+	marker := ""
+	entriesUrl := fmt.Sprint(baseUrl, "/2.0/collaboration_whitelist_entries")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Entries []CollaborationAllowlistEntry `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		if err := c.doRequest(ctx, entriesUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get collaboration allowlist entries: %w", err)
+		}
+
+		allEntries = append(allEntries, res.Entries...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allEntries, nil
+}
+
+// GetCollaborationAllowlistExemptTargets returns all users exempt from the collaboration allowlist of Box enterprise.
+func (c *Client) GetCollaborationAllowlistExemptTargets(ctx context.Context) ([]CollaborationAllowlistExemptTarget, error) {
+	var allTargets []CollaborationAllowlistExemptTarget
+	marker := ""
+	targetsUrl := fmt.Sprint(baseUrl, "/2.0/collaboration_whitelist_exempt_targets")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Targets []CollaborationAllowlistExemptTarget `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		if err := c.doRequest(ctx, targetsUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get collaboration allowlist exempt targets: %w", err)
+		}
+
+		allTargets = append(allTargets, res.Targets...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allTargets, nil
+}
+
+// AddCollaborationAllowlistExemptTarget exempts a Box user from the collaboration allowlist.
+func (c *Client) AddCollaborationAllowlistExemptTarget(ctx context.Context, userId string) error {
+	targetsUrl := fmt.Sprint(baseUrl, "/2.0/collaboration_whitelist_exempt_targets")
+
+	body := map[string]interface{}{
+		"user": BaseType{ID: userId, Type: "user"},
+	}
+
+	if err := c.doRequestWithBody(ctx, http.MethodPost, targetsUrl, body, nil); err != nil {
+		return fmt.Errorf("failed to add collaboration allowlist exempt target: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveCollaborationAllowlistExemptTarget removes the collaboration allowlist exemption of a user.
+func (c *Client) RemoveCollaborationAllowlistExemptTarget(ctx context.Context, targetId string) error {
+	targetUrl := fmt.Sprint(baseUrl, "/2.0/collaboration_whitelist_exempt_targets/", targetId)
+
+	if err := c.doRequestWithBody(ctx, http.MethodDelete, targetUrl, nil, nil); err != nil {
+		return fmt.Errorf("failed to remove collaboration allowlist exempt target: %w", err)
+	}
+
+	return nil
+}
+
+// GetDevicePins returns all device pins of Box enterprise.
+func (c *Client) GetDevicePins(ctx context.Context, enterpriseId string) ([]DevicePin, error) {
+	var allPins []DevicePin
+	marker := ""
+	pinsUrl := fmt.Sprintf("%s/2.0/enterprises/%s/device_pinners", baseUrl, enterpriseId)
+
+	for {
+		var res struct {
+			markerPaginationData
+			Pins []DevicePin `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		if err := c.doRequest(ctx, pinsUrl, &res, q); err != nil {
+			return nil, fmt.Errorf("failed to get device pins: %w", err)
+		}
+
+		allPins = append(allPins, res.Pins...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allPins, nil
+}
+
+// DeleteDevicePin removes a device pin, the device has to be pinned again on next sign in.
+func (c *Client) DeleteDevicePin(ctx context.Context, pinId string) error {
+	pinUrl := fmt.Sprint(baseUrl, "/2.0/device_pinners/", pinId)
+
+	if err := c.doRequestWithBody(ctx, http.MethodDelete, pinUrl, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete device pin: %w", err)
+	}
+
+	return nil
+}
+
+// GetTermsOfServices returns all terms of service of Box enterprise.
+func (c *Client) GetTermsOfServices(ctx context.Context) ([]TermsOfService, error) {
+	tosUrl := fmt.Sprint(baseUrl, "/2.0/terms_of_services")
+
+	var res struct {
+		TotalCount      int              `json:"total_count"`
+		TermsOfServices []TermsOfService `json:"entries"`
+	}
+
+	if err := c.doRequest(ctx, tosUrl, &res, nil); err != nil {
+		return nil, fmt.Errorf("failed to get terms of services: %w", err)
+	}
+
+	return res.TermsOfServices, nil
+}
+
+// GetTermsOfServiceUserStatuses returns the acceptance state of a terms of service for every user who responded to it.
+func (c *Client) GetTermsOfServiceUserStatuses(ctx context.Context, tosId string) ([]TermsOfServiceUserStatus, error) {
+	statusesUrl := fmt.Sprint(baseUrl, "/2.0/terms_of_service_user_statuses")
+
+	var res struct {
+		TotalCount   int                        `json:"total_count"`
+		UserStatuses []TermsOfServiceUserStatus `json:"entries"`
+	}
+
+	q := url.Values{}
+	q.Set("tos_id", tosId)
+	if err := c.doRequest(ctx, statusesUrl, &res, q); err != nil {
+		return nil, fmt.Errorf("failed to get terms of service user statuses: %w", err)
+	}
+
+	return res.UserStatuses, nil
+}
+
+// GetHubs returns all hubs of Box enterprise.
+func (c *Client) GetHubs(ctx context.Context) ([]Hub, error) {
+	var allHubs []Hub
+	marker := ""
+	hubsUrl := fmt.Sprint(baseUrl, "/2.0/enterprise_hubs")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Hubs []Hub `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		if err := c.doVersionedRequest(ctx, hubsUrl, &res, q, hubsApiVersion); err != nil {
+			// enterprises without Box Hubs cannot list hubs.
+			if isFeatureUnavailable(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get hubs: %w", err)
+		}
+
+		allHubs = append(allHubs, res.Hubs...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allHubs, nil
+}
+
+// GetHubCollaborations returns all collaborations of a Box hub.
+func (c *Client) GetHubCollaborations(ctx context.Context, hubId string) ([]HubCollaboration, error) {
+	var allCollaborations []HubCollaboration
+	marker := ""
+	collaborationsUrl := fmt.Sprint(baseUrl, "/2.0/hub_collaborations")
+
+	for {
+		var res struct {
+			markerPaginationData
+			Collaborations []HubCollaboration `json:"entries"`
+		}
+
+		q := markerPaginationQuery(marker, defaultLimit)
+		q.Set("hub_id", hubId)
+		if err := c.doVersionedRequest(ctx, collaborationsUrl, &res, q, hubsApiVersion); err != nil {
+			return nil, fmt.Errorf("failed to get hub collaborations: %w", err)
+		}
+
+		allCollaborations = append(allCollaborations, res.Collaborations...)
+
+		if res.NextMarker == "" {
+			break
+		}
+
+		marker = res.NextMarker
+	}
+
+	return allCollaborations, nil
+}
+
+// EventsQuery filters the enterprise events returned by GetEnterpriseEvents.
+type EventsQuery struct {
+	StreamType     string
+	StreamPosition string
+	EventTypes     []string
+	// CreatedAfter and CreatedBefore are only supported by the admin_logs stream type.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// GetEnterpriseEvents returns one chunk of enterprise events and the stream position to continue from.
+// The chunk is empty once the end of the stream is reached.
+func (c *Client) GetEnterpriseEvents(ctx context.Context, query EventsQuery) ([]Event, string, error) {
+	eventsUrl := fmt.Sprint(baseUrl, "/2.0/events")
+
+	var res struct {
+		ChunkSize          int            `json:"chunk_size"`
+		NextStreamPosition StreamPosition `json:"next_stream_position"`
+		Events             []Event        `json:"entries"`
+	}
+
+	q := url.Values{}
+	q.Set("stream_type", query.StreamType)
+	q.Set("limit", strconv.Itoa(maxEventsLimit))
+	if query.StreamPosition != "" {
+		q.Set("stream_position", query.StreamPosition)
+	}
+	if len(query.EventTypes) > 0 {
+		q.Set("event_type", strings.Join(query.EventTypes, ","))
+	}
+	if !query.CreatedAfter.IsZero() {
+		q.Set("created_after", query.CreatedAfter.Format(time.RFC3339))
+	}
+	if !query.CreatedBefore.IsZero() {
+		q.Set("created_before", query.CreatedBefore.Format(time.RFC3339))
+	}
+
+	if err := c.doRequest(ctx, eventsUrl, &res, q); err != nil {
+		return nil, "", fmt.Errorf("failed to get enterprise events: %w", err)
+	}
+
+	return res.Events, string(res.NextStreamPosition), nil
+}
+
+func (c *Client) doRequest(ctx context.Context, url string, res interface{}, params url.Values) error {
+	return c.makeRequest(ctx, http.MethodGet, url, params, nil, res, "")
+}
+
+// doVersionedRequest requests an endpoint that is only available in the given version of Box API.
+func (c *Client) doVersionedRequest(ctx context.Context, url string, res interface{}, params url.Values, version string) error {
+	return c.makeRequest(ctx, http.MethodGet, url, params, nil, res, version)
+}
+
+// doRequestWithBody sends body encoded as JSON. The response is decoded into res unless res is nil.
+func (c *Client) doRequestWithBody(ctx context.Context, method string, url string, body interface{}, res interface{}) error {
+	return c.makeRequest(ctx, method, url, nil, body, res, "")
+}
+
+func (c *Client) makeRequest(ctx context.Context, method string, url string, params url.Values, body interface{}, res interface{}, version string) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return err
+	}
+
+	if params != nil {
+		req.URL.RawQuery = params.Encode()
+	}
+
+	req.Header.Add("accept", "application/json")
+	if c.token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	}
+	if body != nil {
+		req.Header.Add("content-type", "application/json")
+	}
+	if version != "" {
+		req.Header.Add("box-version", version)
+	}
+	if c.asUser != "" {
+		req.Header.Add("As-User", c.asUser)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	// GET requests return 200, create requests 201 and delete requests 204 if successful.
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errorFromResponse(resp)
+	}
+
+	if res == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isFeatureUnavailable reports whether Box refused a request because the enterprise lacks the add-on behind the
+// endpoint, which Box answers with 403 or 404.
+func isFeatureUnavailable(err error) bool {
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) {
+		return false
+	}
+	return errorResponse.Status == http.StatusForbidden || errorResponse.Status == http.StatusNotFound
+}
+
+// IsForbidden reports whether Box refused a request with 403, e.g. because the application is not allowed to act as
+// users or the user has no access to the item.
+func IsForbidden(err error) bool {
+	var errorResponse *ErrorResponse
+	return errors.As(err, &errorResponse) && errorResponse.Status == http.StatusForbidden
+}
+
+// returns the Box error carried by an unsuccessful response.
+func errorFromResponse(resp *http.Response) error {
+	var errorResponse ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil || errorResponse.Type != errorType {
+		return fmt.Errorf("unexpected status code: %s", resp.Status)
+	}
+	return &errorResponse
+}
@@ -1,5 +1,10 @@
 package box
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 type BaseType struct {
 	ID   string `json:"id"`
 	Type string `json:"type"`
@@ -7,12 +12,108 @@ type BaseType struct {
 
 type User struct {
 	BaseType
-	Enterprise Enterprise `json:"enterprise"`
-	Login      string     `json:"login"`
-	Name       string     `json:"name"`
-	Phone      string     `json:"phone"`
-	Role       string     `json:"role"`
-	Status     string     `json:"status"`
+	Address                       string            `json:"address"`
+	CreatedAt                     string            `json:"created_at"`
+	Enterprise                    Enterprise        `json:"enterprise"`
+	IsExemptFromDeviceLimits      bool              `json:"is_exempt_from_device_limits"`
+	IsExemptFromLoginVerification bool              `json:"is_exempt_from_login_verification"`
+	IsExternalCollabRestricted    bool              `json:"is_external_collab_restricted"`
+	IsPlatformAccessOnly          bool              `json:"is_platform_access_only"`
+	IsSyncEnabled                 bool              `json:"is_sync_enabled"`
+	JobTitle                      string            `json:"job_title"`
+	Language                      string            `json:"language"`
+	Login                         string            `json:"login"`
+	MaxUploadSize                 int64             `json:"max_upload_size"`
+	ModifiedAt                    string            `json:"modified_at"`
+	Name                          string            `json:"name"`
+	NotificationEmail             NotificationEmail `json:"notification_email"`
+	Phone                         string            `json:"phone"`
+	Role                          string            `json:"role"`
+	SpaceAmount                   int64             `json:"space_amount"`
+	SpaceUsed                     int64             `json:"space_used"`
+	Status                        string            `json:"status"`
+	Timezone                      string            `json:"timezone"`
+	TrackingCodes                 []TrackingCode    `json:"tracking_codes"`
+
+	// EmailAliases are not part of the user object and are only set when fetched separately.
+	EmailAliases []EmailAlias `json:"-"`
+
+	// LastLoginAt and LastActivityAt are derived from enterprise events and only set when computed.
+	LastLoginAt    string `json:"-"`
+	LastActivityAt string `json:"-"`
+
+	// ExtraFields holds the values of additionally requested user fields that have no dedicated struct field.
+	ExtraFields map[string]interface{} `json:"-"`
+	raw         map[string]interface{}
+}
+
+// UnmarshalJSON decodes the user and keeps the raw field values, so fields requested through configuration can be picked up later.
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	var decoded user
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &decoded.raw); err != nil {
+		return err
+	}
+
+	*u = User(decoded)
+	return nil
+}
+
+type Folder struct {
+	BaseType
+	Name    string `json:"name"`
+	OwnedBy User   `json:"owned_by"`
+}
+
+// Item is a file, folder or web link in a Box folder.
+type Item struct {
+	BaseType
+	Name    string   `json:"name"`
+	OwnedBy BaseType `json:"owned_by"`
+}
+
+// Collaboration gives a user or group access to a Box file or folder.
+type Collaboration struct {
+	BaseType
+	AccessibleBy struct {
+		BaseType
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	} `json:"accessible_by"`
+	Item   Item   `json:"item"`
+	Role   string `json:"role"`
+	Status string `json:"status"`
+}
+
+// UserRequest holds the fields of a managed Box user to create. Empty fields are not sent.
+type UserRequest struct {
+	Login         string         `json:"login"`
+	Name          string         `json:"name"`
+	Role          string         `json:"role,omitempty"`
+	SpaceAmount   *int64         `json:"space_amount,omitempty"`
+	Status        string         `json:"status,omitempty"`
+	TrackingCodes []TrackingCode `json:"tracking_codes,omitempty"`
+}
+
+type EmailAlias struct {
+	BaseType
+	Email       string `json:"email"`
+	IsConfirmed bool   `json:"is_confirmed"`
+}
+
+type NotificationEmail struct {
+	Email       string `json:"email"`
+	IsConfirmed bool   `json:"is_confirmed"`
+}
+
+type TrackingCode struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
 }
 
 type Enterprise struct {
@@ -22,9 +123,28 @@ type Enterprise struct {
 
 type Group struct {
 	BaseType
+	Description            string `json:"description"`
+	ExternalSyncIdentifier string `json:"external_sync_identifier"`
 	InvitabilityLevel      string `json:"invitability_level"`
 	MemberViewabilityLevel string `json:"member_viewability_level"`
 	Name                   string `json:"name"`
+	Provenance             string `json:"provenance"`
+}
+
+// IsExternallyManaged reports whether the group is synced from an external identity provider, which sets the external
+// sync identifier. The provenance alone does not count, as any API client can set it.
+func (g *Group) IsExternallyManaged() bool {
+	return g.ExternalSyncIdentifier != ""
+}
+
+// GroupRequest holds the fields to set when creating or updating a Box group. Nil fields are not sent.
+type GroupRequest struct {
+	Name                   *string `json:"name,omitempty"`
+	Description            *string `json:"description,omitempty"`
+	Provenance             *string `json:"provenance,omitempty"`
+	ExternalSyncIdentifier *string `json:"external_sync_identifier,omitempty"`
+	InvitabilityLevel      *string `json:"invitability_level,omitempty"`
+	MemberViewabilityLevel *string `json:"member_viewability_level,omitempty"`
 }
 
 type GroupMembership struct {
@@ -33,3 +153,220 @@ type GroupMembership struct {
 	User  User   `json:"user"`
 	Group Group  `json:"group"`
 }
+
+type LegalHoldPolicy struct {
+	BaseType
+	AssignmentCounts struct {
+		File        int `json:"file"`
+		FileVersion int `json:"file_version"`
+		Folder      int `json:"folder"`
+		User        int `json:"user"`
+	} `json:"assignment_counts"`
+	CreatedAt       string `json:"created_at"`
+	CreatedBy       User   `json:"created_by"`
+	Description     string `json:"description"`
+	FilterEndedAt   string `json:"filter_ended_at"`
+	FilterStartedAt string `json:"filter_started_at"`
+	IsOngoing       bool   `json:"is_ongoing"`
+	ModifiedAt      string `json:"modified_at"`
+	PolicyName      string `json:"policy_name"`
+	ReleaseNotes    string `json:"release_notes"`
+	Status          string `json:"status"`
+}
+
+type LegalHoldPolicyAssignment struct {
+	BaseType
+	AssignedAt string   `json:"assigned_at"`
+	AssignedBy User     `json:"assigned_by"`
+	AssignedTo BaseType `json:"assigned_to"`
+}
+
+type RetentionPolicy struct {
+	BaseType
+	AreOwnersNotified bool `json:"are_owners_notified"`
+	AssignmentCounts  struct {
+		Enterprise       int `json:"enterprise"`
+		Folder           int `json:"folder"`
+		MetadataTemplate int `json:"metadata_template"`
+	} `json:"assignment_counts"`
+	CanOwnerExtendRetention bool   `json:"can_owner_extend_retention"`
+	CreatedAt               string `json:"created_at"`
+	CreatedBy               User   `json:"created_by"`
+	Description             string `json:"description"`
+	DispositionAction       string `json:"disposition_action"`
+	ModifiedAt              string `json:"modified_at"`
+	PolicyName              string `json:"policy_name"`
+	PolicyType              string `json:"policy_type"`
+	RetentionLength         string `json:"retention_length"`
+	RetentionType           string `json:"retention_type"`
+	Status                  string `json:"status"`
+}
+
+type RetentionPolicyAssignment struct {
+	BaseType
+	AssignedAt     string   `json:"assigned_at"`
+	AssignedBy     User     `json:"assigned_by"`
+	AssignedTo     BaseType `json:"assigned_to"`
+	StartDateField string   `json:"start_date_field"`
+}
+
+type MetadataTemplate struct {
+	BaseType
+	DisplayName string `json:"displayName"`
+	Fields      []struct {
+		Key     string                   `json:"key"`
+		Options []SecurityClassification `json:"options"`
+	} `json:"fields"`
+	TemplateKey string `json:"templateKey"`
+}
+
+type SecurityClassification struct {
+	ID           string `json:"id"`
+	Key          string `json:"key"`
+	StaticConfig struct {
+		Classification struct {
+			ClassificationDefinition string `json:"classificationDefinition"`
+			ColorID                  int    `json:"colorID"`
+		} `json:"classification"`
+	} `json:"staticConfig"`
+}
+
+type ShieldInformationBarrier struct {
+	BaseType
+	CreatedAt  string     `json:"created_at"`
+	Enterprise Enterprise `json:"enterprise"`
+	Status     string     `json:"status"`
+	UpdatedAt  string     `json:"updated_at"`
+}
+
+type ShieldInformationBarrierSegment struct {
+	BaseType
+	CreatedAt                string   `json:"created_at"`
+	Description              string   `json:"description"`
+	Name                     string   `json:"name"`
+	ShieldInformationBarrier BaseType `json:"shield_information_barrier"`
+	UpdatedAt                string   `json:"updated_at"`
+}
+
+type ShieldInformationBarrierSegmentMember struct {
+	BaseType
+	ShieldInformationBarrierSegment BaseType `json:"shield_information_barrier_segment"`
+	User                            User     `json:"user"`
+}
+
+type ShieldInformationBarrierSegmentRestriction struct {
+	BaseType
+	RestrictedSegment               BaseType `json:"restricted_segment"`
+	ShieldInformationBarrierSegment BaseType `json:"shield_information_barrier_segment"`
+}
+
+type StoragePolicy struct {
+	BaseType
+	Name string `json:"name"`
+}
+
+type StoragePolicyAssignment struct {
+	BaseType
+	AssignedTo    BaseType `json:"assigned_to"`
+	StoragePolicy BaseType `json:"storage_policy"`
+}
+
+type CollaborationAllowlistEntry struct {
+	BaseType
+	CreatedAt string `json:"created_at"`
+	Direction string `json:"direction"`
+	Domain    string `json:"domain"`
+}
+
+type CollaborationAllowlistExemptTarget struct {
+	BaseType
+	CreatedAt  string `json:"created_at"`
+	ModifiedAt string `json:"modified_at"`
+	User       User   `json:"user"`
+}
+
+type DevicePin struct {
+	BaseType
+	CreatedAt   string `json:"created_at"`
+	ModifiedAt  string `json:"modified_at"`
+	OwnedBy     User   `json:"owned_by"`
+	ProductName string `json:"product_name"`
+}
+
+type TermsOfService struct {
+	BaseType
+	CreatedAt  string `json:"created_at"`
+	ModifiedAt string `json:"modified_at"`
+	Status     string `json:"status"`
+	Text       string `json:"text"`
+	TosType    string `json:"tos_type"`
+}
+
+type TermsOfServiceUserStatus struct {
+	BaseType
+	CreatedAt  string   `json:"created_at"`
+	IsAccepted bool     `json:"is_accepted"`
+	ModifiedAt string   `json:"modified_at"`
+	Tos        BaseType `json:"tos"`
+	User       User     `json:"user"`
+}
+
+type Hub struct {
+	BaseType
+	CanNonOwnersInvite                    bool   `json:"can_non_owners_invite"`
+	CreatedAt                             string `json:"created_at"`
+	CreatedBy                             User   `json:"created_by"`
+	Description                           string `json:"description"`
+	IsCollaborationRestrictedToEnterprise bool   `json:"is_collaboration_restricted_to_enterprise"`
+	Title                                 string `json:"title"`
+	UpdatedAt                             string `json:"updated_at"`
+	ViewCount                             int    `json:"view_count"`
+}
+
+type HubCollaboration struct {
+	BaseType
+	AccessibleBy struct {
+		BaseType
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	} `json:"accessible_by"`
+	Hub    BaseType `json:"hub"`
+	Role   string   `json:"role"`
+	Status string   `json:"status"`
+}
+
+type Event struct {
+	Type              string                 `json:"type"`
+	AdditionalDetails map[string]interface{} `json:"additional_details"`
+	CreatedAt         string                 `json:"created_at"`
+	CreatedBy         User                   `json:"created_by"`
+	EventID           string                 `json:"event_id"`
+	EventType         string                 `json:"event_type"`
+	IPAddress         string                 `json:"ip_address"`
+	SessionID         string                 `json:"session_id"`
+	Source            map[string]interface{} `json:"source"`
+}
+
+// StreamPosition is a position in a Box event stream. Box returns it as a number or a string depending on the stream type.
+type StreamPosition string
+
+func (p *StreamPosition) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*p = ""
+		return nil
+	}
+
+	// numeric positions exceed float64 precision, so they are kept as written.
+	if !bytes.HasPrefix(data, []byte(`"`)) {
+		*p = StreamPosition(data)
+		return nil
+	}
+
+	var position string
+	if err := json.Unmarshal(data, &position); err != nil {
+		return err
+	}
+	*p = StreamPosition(position)
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conductorone/baton-box/pkg/box"
+)
+
+const (
+	eventTypeLogin      = "LOGIN"
+	eventTypeAdminLogin = "ADMIN_LOGIN"
+)
+
+// userActivity holds the latest activity of a Box user seen in the enterprise events.
+type userActivity struct {
+	lastLogin      time.Time
+	lastAdminLogin time.Time
+	lastActivity   time.Time
+}
+
+// activityTracker computes the latest login and activity of every user from the enterprise admin events.
+type activityTracker struct {
+	client   *box.Client
+	lookback time.Duration
+
+	activity ttlCache[map[string]*userActivity]
+}
+
+func newActivityTracker(client *box.Client, lookback time.Duration) *activityTracker {
+	return &activityTracker{
+		client:   client,
+		lookback: lookback,
+	}
+}
+
+// get returns the activity of users keyed by user ID. It returns nil when activity tracking is disabled.
+func (a *activityTracker) get(ctx context.Context) (map[string]*userActivity, error) {
+	if a == nil || a.lookback <= 0 {
+		return nil, nil
+	}
+
+	return a.activity.get(ctx, a.fetch)
+}
+
+func (a *activityTracker) fetch(ctx context.Context) (map[string]*userActivity, error) {
+	now := time.Now()
+	activity := make(map[string]*userActivity)
+	query := box.EventsQuery{
+		StreamType:   box.StreamTypeAdminLogs,
+		CreatedAfter: now.Add(-a.lookback),
+	}
+
+	for {
+		events, nextPosition, err := a.client.GetEnterpriseEvents(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			if event.CreatedBy.ID == "" {
+				continue
+			}
+
+			createdAt, err := time.Parse(time.RFC3339, event.CreatedAt)
+			if err != nil {
+				return nil, fmt.Errorf("invalid created_at of event %s: %w", event.EventID, err)
+			}
+
+			ua, ok := activity[event.CreatedBy.ID]
+			if !ok {
+				ua = &userActivity{}
+				activity[event.CreatedBy.ID] = ua
+			}
+
+			ua.lastActivity = latest(ua.lastActivity, createdAt)
+			switch event.EventType {
+			case eventTypeLogin:
+				ua.lastLogin = latest(ua.lastLogin, createdAt)
+			case eventTypeAdminLogin:
+				ua.lastLogin = latest(ua.lastLogin, createdAt)
+				ua.lastAdminLogin = latest(ua.lastAdminLogin, createdAt)
+			}
+		}
+
+		if len(events) == 0 || nextPosition == "" || nextPosition == query.StreamPosition {
+			break
+		}
+
+		query.StreamPosition = nextPosition
+	}
+
+	return activity, nil
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// formatActivityTime formats an activity timestamp for profiles and grant metadata.
+func formatActivityTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
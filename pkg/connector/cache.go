@@ -0,0 +1,52 @@
+package connector
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// cacheTTL is how long fetched data is reused, so a sync fetches it once instead of once per resource.
+const cacheTTL = 30 * time.Minute
+
+// ttlCache holds a value fetched from Box for cacheTTL.
+type ttlCache[T any] struct {
+	mtx       sync.Mutex
+	fetchedAt time.Time
+	valid     bool
+	value     T
+}
+
+// get returns the cached value, calling fetch when there is none or it expired.
+// A failed fetch is not cached. Callers must not modify the returned value.
+func (c *ttlCache[T]) get(ctx context.Context, fetch func(ctx context.Context) (T, error)) (T, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.valid && time.Since(c.fetchedAt) < cacheTTL {
+		return c.value, nil
+	}
+
+	fetchedAt := time.Now()
+	value, err := fetch(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	c.value = value
+	c.fetchedAt = fetchedAt
+	c.valid = true
+
+	return value, nil
+}
+
+// invalidate drops the cached value, the next get fetches it again.
+func (c *ttlCache[T]) invalidate() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var zero T
+	c.value = zero
+	c.valid = false
+}
@@ -0,0 +1,78 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// classificationResourceType syncs the Box Shield classification labels defined in the enterprise.
+// Which label is applied to which folder or file is not synced, as folders and files are not resources of this connector.
+type classificationResourceType struct {
+	resourceType *v2.ResourceType
+	client       *box.Client
+}
+
+func (o *classificationResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Box Shield security classification.
+func classificationResource(classification *box.SecurityClassification, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	definition := classification.StaticConfig.Classification.ClassificationDefinition
+	ret, err := rs.NewResource(
+		classification.Key,
+		resourceTypeClassification,
+		classification.ID,
+		rs.WithDescription(definition),
+		rs.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *classificationResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentId == nil {
+		return nil, "", nil, nil
+	}
+
+	classifications, err := o.client.GetSecurityClassifications(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list security classifications: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, classification := range classifications {
+		classificationCopy := classification
+		cr, err := classificationResource(&classificationCopy, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, cr)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *classificationResourceType) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func (o *classificationResourceType) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func classificationBuilder(client *box.Client) *classificationResourceType {
+	return &classificationResourceType{
+		resourceType: resourceTypeClassification,
+		client:       client,
+	}
+}
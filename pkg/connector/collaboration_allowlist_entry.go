@@ -0,0 +1,75 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type collaborationAllowlistEntryResourceType struct {
+	resourceType *v2.ResourceType
+	client       *box.Client
+}
+
+func (o *collaborationAllowlistEntryResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a domain on the Box collaboration allowlist.
+func collaborationAllowlistEntryResource(entry *box.CollaborationAllowlistEntry, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ret, err := rs.NewResource(
+		entry.Domain,
+		resourceTypeCollaborationAllowlistEntry,
+		entry.ID,
+		rs.WithDescription(fmt.Sprintf("Collaboration allowed with %s (%s)", entry.Domain, entry.Direction)),
+		rs.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *collaborationAllowlistEntryResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentId == nil {
+		return nil, "", nil, nil
+	}
+
+	entries, err := o.client.GetCollaborationAllowlistEntries(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list collaboration allowlist entries: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, entry := range entries {
+		entryCopy := entry
+		er, err := collaborationAllowlistEntryResource(&entryCopy, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, er)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *collaborationAllowlistEntryResourceType) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func (o *collaborationAllowlistEntryResourceType) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func collaborationAllowlistEntryBuilder(client *box.Client) *collaborationAllowlistEntryResourceType {
+	return &collaborationAllowlistEntryResourceType{
+		resourceType: resourceTypeCollaborationAllowlistEntry,
+		client:       client,
+	}
+}
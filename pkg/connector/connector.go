@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/conductorone/baton-box/pkg/box"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -19,7 +20,7 @@ var (
 		Traits: []v2.ResourceType_Trait{
 			v2.ResourceType_TRAIT_USER,
 		},
-		Annotations: annotationsForUserResourceType(),
+		Annotations: annotationsForSkipEntitlementsAndGrants(),
 	}
 	resourceTypeGroup = &v2.ResourceType{
 		Id:          "group",
@@ -39,13 +40,78 @@ var (
 			v2.ResourceType_TRAIT_ROLE,
 		},
 	}
+	resourceTypeLegalHoldPolicy = &v2.ResourceType{
+		Id:          "legal_hold_policy",
+		DisplayName: "Legal Hold Policy",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+	}
+	resourceTypeRetentionPolicy = &v2.ResourceType{
+		Id:          "retention_policy",
+		DisplayName: "Retention Policy",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+	}
+	resourceTypeClassification = &v2.ResourceType{
+		Id:          "classification",
+		DisplayName: "Security Classification",
+		Annotations: annotationsForSkipEntitlementsAndGrants(),
+	}
+	resourceTypeShieldInformationBarrier = &v2.ResourceType{
+		Id:          "shield_information_barrier",
+		DisplayName: "Shield Information Barrier",
+		Annotations: annotationsForSkipEntitlementsAndGrants(),
+	}
+	resourceTypeShieldInformationBarrierSegment = &v2.ResourceType{
+		Id:          "shield_information_barrier_segment",
+		DisplayName: "Shield Information Barrier Segment",
+	}
+	resourceTypeStoragePolicy = &v2.ResourceType{
+		Id:          "storage_policy",
+		DisplayName: "Storage Policy",
+	}
+	resourceTypeCollaborationAllowlistEntry = &v2.ResourceType{
+		Id:          "collaboration_allowlist_entry",
+		DisplayName: "Collaboration Allowlist Entry",
+		Annotations: annotationsForSkipEntitlementsAndGrants(),
+	}
+	resourceTypeDevicePin = &v2.ResourceType{
+		Id:          "device_pin",
+		DisplayName: "Device Pin",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+	}
+	resourceTypeTermsOfService = &v2.ResourceType{
+		Id:          "terms_of_service",
+		DisplayName: "Terms of Service",
+	}
+	resourceTypeHub = &v2.ResourceType{
+		Id:          "hub",
+		DisplayName: "Hub",
+	}
 )
 
 type Box struct {
-	client *box.Client
+	client                    *box.Client
+	activity                  *activityTracker
+	users                     *enterpriseUsers
+	storagePolicies           *storagePolicyAssignments
+	allowExternalGroupChanges bool
 }
 
-func New(ctx context.Context, clientId string, clientSecret string, enterpriseId string) (*Box, error) {
+func New(
+	ctx context.Context,
+	clientId string,
+	clientSecret string,
+	enterpriseId string,
+	userType string,
+	userFields []string,
+	activityLookback time.Duration,
+	allowExternalGroupChanges bool,
+) (*Box, error) {
 	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
 	if err != nil {
 		return nil, err
@@ -56,8 +122,15 @@ func New(ctx context.Context, clientId string, clientSecret string, enterpriseId
 		return nil, fmt.Errorf("box-connector: failed to get token: %w", err)
 	}
 
+	client := box.NewClient(httpClient, token, userType, userFields)
+	users := newEnterpriseUsers(client)
+
 	return &Box{
-		client: box.NewClient(httpClient, token),
+		client:                    client,
+		activity:                  newActivityTracker(client, activityLookback),
+		users:                     users,
+		storagePolicies:           newStoragePolicyAssignments(client, users),
+		allowExternalGroupChanges: allowExternalGroupChanges,
 	}, nil
 }
 
@@ -74,7 +147,7 @@ func (b *Box) Validate(ctx context.Context) (annotations.Annotations, error) {
 		return nil, fmt.Errorf("box-connector: failed to authenticate: %w", err)
 	}
 
-	if currentUser.Role != "admin" {
+	if currentUser.Role != box.UserRoleAdmin {
 		return nil, fmt.Errorf("box-connector: user is not an admin")
 	}
 
@@ -83,9 +156,19 @@ func (b *Box) Validate(ctx context.Context) (annotations.Annotations, error) {
 
 func (b *Box) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncer {
 	return []connectorbuilder.ResourceSyncer{
-		userBuilder(b.client),
-		groupBuilder(b.client),
+		userBuilder(b.client, b.activity),
+		groupBuilder(b.client, b.users, b.allowExternalGroupChanges),
 		enterpriseBuilder(b.client),
-		roleBuilder(b.client),
+		roleBuilder(b.client, b.activity),
+		legalHoldPolicyBuilder(b.client),
+		retentionPolicyBuilder(b.client),
+		classificationBuilder(b.client),
+		shieldInformationBarrierBuilder(b.client),
+		shieldInformationBarrierSegmentBuilder(b.client),
+		storagePolicyBuilder(b.client, b.storagePolicies),
+		collaborationAllowlistEntryBuilder(b.client),
+		devicePinBuilder(b.client),
+		termsOfServiceBuilder(b.client),
+		hubBuilder(b.client),
 	}
 }
@@ -0,0 +1,140 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	grant "github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const owner = "owner"
+
+type devicePinResourceType struct {
+	resourceType *v2.ResourceType
+	client       *box.Client
+}
+
+func (o *devicePinResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Box device pin. The owner is kept in the profile,
+// so the owner grant can be built without fetching the pin again.
+func devicePinResource(pin *box.DevicePin, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"owner_id":     pin.OwnedBy.ID,
+		"owner_login":  pin.OwnedBy.Login,
+		"product_name": pin.ProductName,
+		"created_at":   pin.CreatedAt,
+		"modified_at":  pin.ModifiedAt,
+	}
+
+	ret, err := rs.NewResource(
+		fmt.Sprintf("%s (%s)", pin.ProductName, pin.OwnedBy.Login),
+		resourceTypeDevicePin,
+		pin.ID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithAppTrait(rs.WithAppProfile(profile)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *devicePinResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentId == nil {
+		return nil, "", nil, nil
+	}
+
+	pins, err := o.client.GetDevicePins(ctx, parentId.Resource)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list device pins: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, pin := range pins {
+		pinCopy := pin
+		pr, err := devicePinResource(&pinCopy, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, pr)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *devicePinResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	ownerOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeUser),
+		ent.WithDescription(fmt.Sprintf("%s of %s Box device pin", owner, resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s device pin %s", resource.DisplayName, owner)),
+	}
+
+	ownerEn := ent.NewAssignmentEntitlement(resource, owner, ownerOptions...)
+	rv = append(rv, ownerEn)
+
+	return rv, "", nil, nil
+}
+
+func (o *devicePinResourceType) Grants(ctx context.Context, resource *v2.Resource, token *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	appTrait, err := rs.GetAppTrait(resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	ownerUserId, ok := rs.GetProfileStringValue(appTrait.Profile, "owner_id")
+	if !ok || ownerUserId == "" {
+		return nil, "", nil, fmt.Errorf("box-connector: device pin %s has no owner", resource.Id.Resource)
+	}
+
+	ownerId, err := rs.NewResourceID(resourceTypeUser, ownerUserId)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	ownerGrant := grant.NewGrant(resource, owner, ownerId)
+
+	return []*v2.Grant{ownerGrant}, "", nil, nil
+}
+
+func (o *devicePinResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	l.Warn(
+		"box-connector: device pins are created by Box when a user signs in on a device and cannot be granted",
+		zap.String("principal_type", principal.Id.ResourceType),
+		zap.String("principal_id", principal.Id.Resource),
+	)
+
+	return nil, fmt.Errorf("box-connector: device pins cannot be granted")
+}
+
+// Revoke deletes the device pin, the owner has to pin the device again on next sign in.
+func (o *devicePinResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	err := o.client.DeleteDevicePin(ctx, grant.Entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("box-connector: failed to delete device pin: %w", err)
+	}
+
+	return nil, nil
+}
+
+func devicePinBuilder(client *box.Client) *devicePinResourceType {
+	return &devicePinResourceType{
+		resourceType: resourceTypeDevicePin,
+		client:       client,
+	}
+}
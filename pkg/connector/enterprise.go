@@ -12,8 +12,13 @@ import (
 	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	grant "github.com/conductorone/baton-sdk/pkg/types/grant"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
+// collaborationExempt is the entitlement of users exempt from the collaboration allowlist.
+const collaborationExempt = "collaboration_exempt"
+
 type enterpriseResourceType struct {
 	resourceType *v2.ResourceType
 	client       *box.Client
@@ -37,6 +42,15 @@ func enterpriseResource(ctx context.Context, policy box.Enterprise) (*v2.Resourc
 			&v2.ChildResourceType{ResourceTypeId: resourceTypeUser.Id},
 			&v2.ChildResourceType{ResourceTypeId: resourceTypeGroup.Id},
 			&v2.ChildResourceType{ResourceTypeId: resourceTypeRole.Id},
+			&v2.ChildResourceType{ResourceTypeId: resourceTypeLegalHoldPolicy.Id},
+			&v2.ChildResourceType{ResourceTypeId: resourceTypeRetentionPolicy.Id},
+			&v2.ChildResourceType{ResourceTypeId: resourceTypeClassification.Id},
+			&v2.ChildResourceType{ResourceTypeId: resourceTypeShieldInformationBarrier.Id},
+			&v2.ChildResourceType{ResourceTypeId: resourceTypeStoragePolicy.Id},
+			&v2.ChildResourceType{ResourceTypeId: resourceTypeCollaborationAllowlistEntry.Id},
+			&v2.ChildResourceType{ResourceTypeId: resourceTypeDevicePin.Id},
+			&v2.ChildResourceType{ResourceTypeId: resourceTypeTermsOfService.Id},
+			&v2.ChildResourceType{ResourceTypeId: resourceTypeHub.Id},
 		),
 	}
 
@@ -75,7 +89,15 @@ func (o *enterpriseResourceType) Entitlements(ctx context.Context, resource *v2.
 	}
 
 	assignmentEn := ent.NewAssignmentEntitlement(resource, member, assigmentOptions...)
-	rv = append(rv, assignmentEn)
+
+	exemptOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeUser),
+		ent.WithDescription(fmt.Sprintf("Exempt from collaboration restrictions of %s Box enterprise", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s enterprise exempt from collaboration restrictions", resource.DisplayName)),
+	}
+	exemptEn := ent.NewPermissionEntitlement(resource, collaborationExempt, exemptOptions...)
+
+	rv = append(rv, assignmentEn, exemptEn)
 
 	return rv, "", nil, nil
 }
@@ -97,5 +119,88 @@ func (o *enterpriseResourceType) Grants(ctx context.Context, resource *v2.Resour
 		rv = append(rv, membershipGrant)
 	}
 
+	exemptTargets, err := o.client.GetCollaborationAllowlistExemptTargets(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list collaboration allowlist exempt targets: %w", err)
+	}
+
+	for _, exemptTarget := range exemptTargets {
+		exemptTargetCopy := exemptTarget
+		ur, err := userResource(&exemptTargetCopy.User, resource.Id)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		exemptGrant := grant.NewGrant(resource, collaborationExempt, ur.Id)
+		rv = append(rv, exemptGrant)
+	}
+
 	return rv, "", nil, nil
 }
+
+func (o *enterpriseResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if entitlement.Slug != collaborationExempt {
+		return nil, fmt.Errorf("box-connector: only the %s entitlement can be granted on the enterprise", collaborationExempt)
+	}
+
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		l.Warn(
+			"box-connector: only users can be exempt from collaboration restrictions",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("box-connector: only users can be exempt from collaboration restrictions")
+	}
+
+	err := o.client.AddCollaborationAllowlistExemptTarget(ctx, principal.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("box-connector: failed to exempt user from collaboration restrictions: %w", err)
+	}
+
+	return nil, nil
+}
+
+func (o *enterpriseResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	entitlement := grant.Entitlement
+
+	if entitlement.Slug != collaborationExempt {
+		return nil, fmt.Errorf("box-connector: only the %s entitlement can be revoked on the enterprise", collaborationExempt)
+	}
+
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		l.Warn(
+			"box-connector: only users can be exempt from collaboration restrictions",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("box-connector: only users can be exempt from collaboration restrictions")
+	}
+
+	exemptTargets, err := o.client.GetCollaborationAllowlistExemptTargets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("box-connector: failed to list collaboration allowlist exempt targets: %w", err)
+	}
+
+	for _, exemptTarget := range exemptTargets {
+		if exemptTarget.User.ID != principal.Id.Resource {
+			continue
+		}
+
+		err := o.client.RemoveCollaborationAllowlistExemptTarget(ctx, exemptTarget.ID)
+		if err != nil {
+			return nil, fmt.Errorf("box-connector: failed to remove collaboration restrictions exemption: %w", err)
+		}
+		return nil, nil
+	}
+
+	l.Info(
+		"box-connector: user is not exempt from collaboration restrictions",
+		zap.String("user_id", principal.Id.Resource),
+	)
+
+	return nil, nil
+}
@@ -0,0 +1,45 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-box/pkg/box"
+)
+
+// enterpriseUsers lists the users of the enterprise for grants that apply to every member of the enterprise.
+type enterpriseUsers struct {
+	client *box.Client
+
+	users   ttlCache[[]box.User]
+	managed ttlCache[[]box.User]
+}
+
+func newEnterpriseUsers(client *box.Client) *enterpriseUsers {
+	return &enterpriseUsers{
+		client: client,
+	}
+}
+
+// get returns the users of the enterprise of the configured user type. The returned slice must not be modified.
+func (e *enterpriseUsers) get(ctx context.Context) ([]box.User, error) {
+	return e.users.get(ctx, e.client.GetUsers)
+}
+
+// getManaged returns the managed users of the enterprise that are people, leaving out external users and
+// service accounts. The returned slice must not be modified.
+func (e *enterpriseUsers) getManaged(ctx context.Context) ([]box.User, error) {
+	return e.managed.get(ctx, func(ctx context.Context) ([]box.User, error) {
+		users, err := e.client.GetManagedUsers(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		managed := make([]box.User, 0, len(users))
+		for i := range users {
+			if !isServiceAccount(&users[i]) {
+				managed = append(managed, users[i])
+			}
+		}
+		return managed, nil
+	})
+}
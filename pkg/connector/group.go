@@ -11,16 +11,24 @@ import (
 	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
 	grant "github.com/conductorone/baton-sdk/pkg/types/grant"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 const (
 	member = "member"
 	admin  = "admin"
+
+	// manageMembership is the entitlement of users who can add and remove members of a group.
+	manageMembership = "manage_membership"
 )
 
 type groupResourceType struct {
 	resourceType *v2.ResourceType
 	client       *box.Client
+	users        *enterpriseUsers
+	// allowExternalGroupChanges allows changing memberships of groups synced from an external identity provider.
+	allowExternalGroupChanges bool
 }
 
 func (g *groupResourceType) ResourceType(_ context.Context) *v2.ResourceType {
@@ -30,9 +38,22 @@ func (g *groupResourceType) ResourceType(_ context.Context) *v2.ResourceType {
 // Create a new connector resource for a Box group.
 func groupResource(group *box.Group, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
 	profile := map[string]interface{}{
-		"group_id":   group.ID,
-		"group_name": group.Name,
+		"group_id":                 group.ID,
+		"group_name":               group.Name,
+		"invitability_level":       group.InvitabilityLevel,
+		"member_viewability_level": group.MemberViewabilityLevel,
+	}
+
+	if group.Description != "" {
+		profile["description"] = group.Description
 	}
+	if group.Provenance != "" {
+		profile["provenance"] = group.Provenance
+	}
+	if group.ExternalSyncIdentifier != "" {
+		profile["external_sync_identifier"] = group.ExternalSyncIdentifier
+	}
+	profile["externally_managed"] = group.IsExternallyManaged()
 
 	groupTraitOptions := []rs.GroupTraitOption{rs.WithGroupProfile(profile)}
 
@@ -82,7 +103,14 @@ func (g *groupResourceType) Entitlements(_ context.Context, resource *v2.Resourc
 	permissionOptions := PopulateOptions(resource.DisplayName, admin, resource.Id.Resource)
 	permissionEn := ent.NewPermissionEntitlement(resource, admin, permissionOptions...)
 
-	rv = append(rv, assignmentEn, permissionEn)
+	manageOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeUser),
+		ent.WithDescription(fmt.Sprintf("Can add and remove members of Box %s group", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s group can manage membership", resource.DisplayName)),
+	}
+	manageEn := ent.NewPermissionEntitlement(resource, manageMembership, manageOptions...)
+
+	rv = append(rv, assignmentEn, permissionEn, manageEn)
 
 	return rv, "", nil, nil
 }
@@ -90,11 +118,17 @@ func (g *groupResourceType) Entitlements(_ context.Context, resource *v2.Resourc
 func (g *groupResourceType) Grants(ctx context.Context, resource *v2.Resource, token *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
 	var rv []*v2.Grant
 
+	invitabilityLevel, err := g.invitabilityLevel(ctx, resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
 	groupMemberships, err := g.client.GetGroupMemberships(ctx, resource.Id.Resource)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
+	granted := make(map[string]bool)
 	for _, groupMembership := range groupMemberships {
 		groupMembershipCopy := groupMembership
 		ur, err := userResource(&groupMembershipCopy.User, resource.Id)
@@ -104,18 +138,222 @@ func (g *groupResourceType) Grants(ctx context.Context, resource *v2.Resource, t
 		membershipGrant := grant.NewGrant(resource, member, ur.Id)
 		rv = append(rv, membershipGrant)
 
-		if groupMembership.Role == admin {
+		if groupMembership.Role == box.GroupRoleAdmin {
 			adminsGrant := grant.NewGrant(resource, admin, ur.Id)
 			rv = append(rv, adminsGrant)
 		}
+
+		// group admins can always manage members, regular members only when the group allows it.
+		if groupMembership.Role == box.GroupRoleAdmin || invitabilityLevel == box.GroupLevelAdminsAndMembers {
+			manageGrant := grant.NewGrant(resource, manageMembership, ur.Id)
+			rv = append(rv, manageGrant)
+			granted[grantKey(manageMembership, groupMembership.User.ID)] = true
+		}
+	}
+
+	// every managed user of the enterprise can add members.
+	if invitabilityLevel == box.GroupLevelAllManagedUsers {
+		users, err := g.users.getManaged(ctx)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("box-connector: failed to list users: %w", err)
+		}
+
+		userIDs := make([]string, 0, len(users))
+		for _, user := range users {
+			userIDs = append(userIDs, user.ID)
+		}
+
+		manageGrants, err := expandedGrants(resource, manageMembership, userIDs, granted, map[string]interface{}{
+			"granted_through_enterprise": true,
+		})
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, manageGrants...)
 	}
 
 	return rv, "", nil, nil
 }
 
-func groupBuilder(client *box.Client) *groupResourceType {
+// invitabilityLevel returns who can add members to the group, read from the group profile when it is available.
+func (g *groupResourceType) invitabilityLevel(ctx context.Context, resource *v2.Resource) (string, error) {
+	groupTrait, err := rs.GetGroupTrait(resource)
+	if err == nil {
+		if level, ok := rs.GetProfileStringValue(groupTrait.Profile, "invitability_level"); ok && level != "" {
+			return level, nil
+		}
+	}
+
+	group, err := g.client.GetGroup(ctx, resource.Id.Resource)
+	if err != nil {
+		return "", fmt.Errorf("box-connector: failed to get group: %w", err)
+	}
+
+	return group.InvitabilityLevel, nil
+}
+
+// checkGroupChangeAllowed returns an error when the memberships of the group must not be changed by the connector,
+// because the identity provider that syncs the group would silently revert the change.
+func (g *groupResourceType) checkGroupChangeAllowed(ctx context.Context, groupId string) error {
+	if g.allowExternalGroupChanges {
+		return nil
+	}
+
+	group, err := g.client.GetGroup(ctx, groupId)
+	if err != nil {
+		return fmt.Errorf("box-connector: failed to get group: %w", err)
+	}
+
+	if group.IsExternallyManaged() {
+		return fmt.Errorf(
+			"box-connector: group %s is managed by %q and changes to its members would be reverted, "+
+				"change the membership in the identity provider or enable --allow-external-group-changes",
+			group.Name,
+			externalSource(&group),
+		)
+	}
+
+	return nil
+}
+
+// externalSource returns a human readable name of the source a Box group is synced from.
+func externalSource(group *box.Group) string {
+	if group.Provenance != "" {
+		return group.Provenance
+	}
+	return group.ExternalSyncIdentifier
+}
+
+// findGroupMembership returns the membership of the user in the group, or nil when the user is not a member.
+func (g *groupResourceType) findGroupMembership(ctx context.Context, groupId string, userId string) (*box.GroupMembership, error) {
+	groupMemberships, err := g.client.GetGroupMemberships(ctx, groupId)
+	if err != nil {
+		return nil, fmt.Errorf("box-connector: failed to list group memberships: %w", err)
+	}
+
+	for _, groupMembership := range groupMemberships {
+		if groupMembership.User.ID == userId {
+			groupMembershipCopy := groupMembership
+			return &groupMembershipCopy, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (g *groupResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		l.Warn(
+			"box-connector: only users can be added to a group",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("box-connector: only users can be added to a group")
+	}
+
+	if entitlement.Slug == manageMembership {
+		return nil, fmt.Errorf("box-connector: %s is derived from the group settings and cannot be provisioned directly", manageMembership)
+	}
+
+	groupId := entitlement.Resource.Id.Resource
+	if err := g.checkGroupChangeAllowed(ctx, groupId); err != nil {
+		return nil, err
+	}
+
+	role := member
+	if entitlement.Slug == admin {
+		role = admin
+	}
+
+	groupMembership, err := g.findGroupMembership(ctx, groupId, principal.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case groupMembership == nil:
+		_, err := g.client.AddGroupMembership(ctx, groupId, principal.Id.Resource, role)
+		if err != nil {
+			return nil, fmt.Errorf("box-connector: failed to add group membership: %w", err)
+		}
+	case role == admin && groupMembership.Role != box.GroupRoleAdmin:
+		err := g.client.UpdateGroupMembership(ctx, groupMembership.ID, box.GroupRoleAdmin)
+		if err != nil {
+			return nil, fmt.Errorf("box-connector: failed to update group membership: %w", err)
+		}
+	default:
+		l.Info(
+			"box-connector: user already has the group entitlement",
+			zap.String("group_id", groupId),
+			zap.String("user_id", principal.Id.Resource),
+			zap.String("entitlement", entitlement.Slug),
+		)
+	}
+
+	return nil, nil
+}
+
+func (g *groupResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	entitlement := grant.Entitlement
+
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		l.Warn(
+			"box-connector: only users can be removed from a group",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("box-connector: only users can be removed from a group")
+	}
+
+	if entitlement.Slug == manageMembership {
+		return nil, fmt.Errorf("box-connector: %s is derived from the group settings and cannot be provisioned directly", manageMembership)
+	}
+
+	groupId := entitlement.Resource.Id.Resource
+	if err := g.checkGroupChangeAllowed(ctx, groupId); err != nil {
+		return nil, err
+	}
+
+	groupMembership, err := g.findGroupMembership(ctx, groupId, principal.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case groupMembership == nil:
+		l.Info(
+			"box-connector: user is not a member of the group",
+			zap.String("group_id", groupId),
+			zap.String("user_id", principal.Id.Resource),
+		)
+	case entitlement.Slug == admin:
+		// revoking admin keeps the user in the group as a regular member.
+		if groupMembership.Role == box.GroupRoleAdmin {
+			err := g.client.UpdateGroupMembership(ctx, groupMembership.ID, box.GroupRoleMember)
+			if err != nil {
+				return nil, fmt.Errorf("box-connector: failed to update group membership: %w", err)
+			}
+		}
+	default:
+		err := g.client.RemoveGroupMembership(ctx, groupMembership.ID)
+		if err != nil {
+			return nil, fmt.Errorf("box-connector: failed to remove group membership: %w", err)
+		}
+	}
+
+	return nil, nil
+}
+
+func groupBuilder(client *box.Client, users *enterpriseUsers, allowExternalGroupChanges bool) *groupResourceType {
 	return &groupResourceType{
-		resourceType: resourceTypeGroup,
-		client:       client,
+		resourceType:              resourceTypeGroup,
+		client:                    client,
+		users:                     users,
+		allowExternalGroupChanges: allowExternalGroupChanges,
 	}
 }
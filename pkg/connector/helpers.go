@@ -1,16 +1,23 @@
 package connector
 
 import (
+	"context"
 	"fmt"
+	"sync"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
 	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
-func annotationsForUserResourceType() annotations.Annotations {
+// perUserRequestConcurrency limits the number of requests made for individual users running at once.
+const perUserRequestConcurrency = 10
+
+func annotationsForSkipEntitlementsAndGrants() annotations.Annotations {
 	annos := annotations.Annotations{}
 	annos.Update(&v2.SkipEntitlementsAndGrants{})
 	return annos
@@ -31,3 +38,81 @@ func PopulateOptions(displayName, permission, resource string) []ent.Entitlement
 	}
 	return options
 }
+
+// grantKey identifies a grant of an entitlement to a user, to avoid emitting the same grant twice.
+func grantKey(entitlement string, userID string) string {
+	return entitlement + ":" + userID
+}
+
+// expandedGrants grants the entitlement to every user that has not been granted it yet, recording it in granted.
+// baton-sdk v0.1.5 cannot expand a grant to a group or to the enterprise, so the users reached through them get their
+// own grants, with metadata telling where the access comes from.
+func expandedGrants(
+	resource *v2.Resource,
+	entitlement string,
+	userIDs []string,
+	granted map[string]bool,
+	metadata map[string]interface{},
+) ([]*v2.Grant, error) {
+	var rv []*v2.Grant
+	for _, userID := range userIDs {
+		key := grantKey(entitlement, userID)
+		if granted[key] {
+			continue
+		}
+		granted[key] = true
+
+		principalId, err := rs.NewResourceID(resourceTypeUser, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		rv = append(rv, grant.NewGrant(resource, entitlement, principalId, grant.WithGrantMetadata(metadata)))
+	}
+
+	return rv, nil
+}
+
+// forEachConcurrently calls fn for every index in [0, n) running at most limit calls at once.
+// It returns the first error encountered, after all started calls have finished.
+func forEachConcurrently(ctx context.Context, n int, limit int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, limit)
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
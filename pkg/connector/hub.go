@@ -0,0 +1,153 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	grant "github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// hubCollaborationAccepted is the status of hub collaborations that give access to the hub.
+const hubCollaborationAccepted = "accepted"
+
+// hubRoles are the roles a hub collaboration can have.
+var hubRoles = []string{"co-owner", "editor", "viewer"}
+
+type hubResourceType struct {
+	resourceType *v2.ResourceType
+	client       *box.Client
+}
+
+func (o *hubResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Box hub.
+func hubResource(hub *box.Hub, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ret, err := rs.NewResource(
+		hub.Title,
+		resourceTypeHub,
+		hub.ID,
+		rs.WithDescription(hub.Description),
+		rs.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *hubResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentId == nil {
+		return nil, "", nil, nil
+	}
+
+	hubs, err := o.client.GetHubs(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list hubs: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, hub := range hubs {
+		hubCopy := hub
+		hr, err := hubResource(&hubCopy, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, hr)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *hubResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	for _, role := range hubRoles {
+		roleOptions := []ent.EntitlementOption{
+			ent.WithGrantableTo(resourceTypeUser, resourceTypeGroup),
+			ent.WithDescription(fmt.Sprintf("%s of %s Box hub", titleCase(role), resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s hub %s", resource.DisplayName, role)),
+		}
+		rv = append(rv, ent.NewPermissionEntitlement(resource, role, roleOptions...))
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *hubResourceType) Grants(ctx context.Context, resource *v2.Resource, token *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	collaborations, err := o.client.GetHubCollaborations(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list hub collaborations: %w", err)
+	}
+
+	var rv []*v2.Grant
+	var groupCollaborations []box.HubCollaboration
+	granted := make(map[string]bool)
+	for _, collaboration := range collaborations {
+		if collaboration.Status != hubCollaborationAccepted {
+			continue
+		}
+
+		var principalType *v2.ResourceType
+		switch collaboration.AccessibleBy.Type {
+		case resourceTypeUser.Id:
+			principalType = resourceTypeUser
+		case resourceTypeGroup.Id:
+			principalType = resourceTypeGroup
+		default:
+			continue
+		}
+
+		principalId, err := rs.NewResourceID(principalType, collaboration.AccessibleBy.ID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		collaborationGrant := grant.NewGrant(resource, collaboration.Role, principalId)
+		rv = append(rv, collaborationGrant)
+
+		if principalType == resourceTypeUser {
+			granted[grantKey(collaboration.Role, collaboration.AccessibleBy.ID)] = true
+		} else {
+			groupCollaborations = append(groupCollaborations, collaboration)
+		}
+	}
+
+	// direct collaborations take precedence over access through a group.
+	for _, collaboration := range groupCollaborations {
+		memberships, err := o.client.GetGroupMemberships(ctx, collaboration.AccessibleBy.ID)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("box-connector: failed to list group memberships: %w", err)
+		}
+
+		userIDs := make([]string, 0, len(memberships))
+		for _, membership := range memberships {
+			userIDs = append(userIDs, membership.User.ID)
+		}
+
+		memberGrants, err := expandedGrants(resource, collaboration.Role, userIDs, granted, map[string]interface{}{
+			"granted_through_group": collaboration.AccessibleBy.ID,
+		})
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, memberGrants...)
+	}
+
+	return rv, "", nil, nil
+}
+
+func hubBuilder(client *box.Client) *hubResourceType {
+	return &hubResourceType{
+		resourceType: resourceTypeHub,
+		client:       client,
+	}
+}
@@ -0,0 +1,137 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	grant "github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const assigned = "assigned"
+
+type legalHoldPolicyResourceType struct {
+	resourceType *v2.ResourceType
+	client       *box.Client
+}
+
+func (o *legalHoldPolicyResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Box legal hold policy.
+func legalHoldPolicyResource(policy *box.LegalHoldPolicy, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"policy_id":         policy.ID,
+		"policy_name":       policy.PolicyName,
+		"status":            policy.Status,
+		"is_ongoing":        policy.IsOngoing,
+		"filter_started_at": policy.FilterStartedAt,
+		"filter_ended_at":   policy.FilterEndedAt,
+		"release_notes":     policy.ReleaseNotes,
+		"created_at":        policy.CreatedAt,
+		"created_by":        policy.CreatedBy.Login,
+		"modified_at":       policy.ModifiedAt,
+		"assigned_users":    policy.AssignmentCounts.User,
+		"assigned_folders":  policy.AssignmentCounts.Folder,
+		"assigned_files":    policy.AssignmentCounts.File + policy.AssignmentCounts.FileVersion,
+	}
+
+	ret, err := rs.NewResource(
+		policy.PolicyName,
+		resourceTypeLegalHoldPolicy,
+		policy.ID,
+		rs.WithDescription(policy.Description),
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithAppTrait(rs.WithAppProfile(profile)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *legalHoldPolicyResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentId == nil {
+		return nil, "", nil, nil
+	}
+
+	policies, err := o.client.GetLegalHoldPolicies(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list legal hold policies: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, policy := range policies {
+		policyCopy := policy
+		pr, err := legalHoldPolicyResource(&policyCopy, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, pr)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *legalHoldPolicyResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeUser),
+		ent.WithDescription(fmt.Sprintf("Held by %s Box legal hold policy", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s legal hold policy %s", resource.DisplayName, assigned)),
+	}
+
+	assignmentEn := ent.NewAssignmentEntitlement(resource, assigned, assignmentOptions...)
+	rv = append(rv, assignmentEn)
+
+	return rv, "", nil, nil
+}
+
+// Grants returns the users held by the policy. Folders and files are not synced as resources,
+// so their assignments are only reflected in the assignment counts of the policy profile.
+func (o *legalHoldPolicyResourceType) Grants(ctx context.Context, resource *v2.Resource, token *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	assignments, err := o.client.GetLegalHoldPolicyAssignments(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list legal hold policy assignments: %w", err)
+	}
+
+	var rv []*v2.Grant
+	for _, assignment := range assignments {
+		if assignment.AssignedTo.Type != resourceTypeUser.Id {
+			continue
+		}
+
+		userId, err := rs.NewResourceID(resourceTypeUser, assignment.AssignedTo.ID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		assignmentGrant := grant.NewGrant(
+			resource,
+			assigned,
+			userId,
+			grant.WithGrantMetadata(map[string]interface{}{
+				"assigned_at": assignment.AssignedAt,
+				"assigned_by": assignment.AssignedBy.Login,
+			}),
+		)
+		rv = append(rv, assignmentGrant)
+	}
+
+	return rv, "", nil, nil
+}
+
+func legalHoldPolicyBuilder(client *box.Client) *legalHoldPolicyResourceType {
+	return &legalHoldPolicyResourceType{
+		resourceType: resourceTypeLegalHoldPolicy,
+		client:       client,
+	}
+}
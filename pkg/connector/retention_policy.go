@@ -0,0 +1,138 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	grant "github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type retentionPolicyResourceType struct {
+	resourceType *v2.ResourceType
+	client       *box.Client
+}
+
+func (o *retentionPolicyResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Box retention policy.
+func retentionPolicyResource(policy *box.RetentionPolicy, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"policy_id":                   policy.ID,
+		"policy_name":                 policy.PolicyName,
+		"policy_type":                 policy.PolicyType,
+		"retention_type":              policy.RetentionType,
+		"retention_length":            policy.RetentionLength,
+		"disposition_action":          policy.DispositionAction,
+		"status":                      policy.Status,
+		"can_owner_extend_retention":  policy.CanOwnerExtendRetention,
+		"are_owners_notified":         policy.AreOwnersNotified,
+		"created_at":                  policy.CreatedAt,
+		"created_by":                  policy.CreatedBy.Login,
+		"modified_at":                 policy.ModifiedAt,
+		"assigned_enterprises":        policy.AssignmentCounts.Enterprise,
+		"assigned_folders":            policy.AssignmentCounts.Folder,
+		"assigned_metadata_templates": policy.AssignmentCounts.MetadataTemplate,
+	}
+
+	ret, err := rs.NewResource(
+		policy.PolicyName,
+		resourceTypeRetentionPolicy,
+		policy.ID,
+		rs.WithDescription(policy.Description),
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithAppTrait(rs.WithAppProfile(profile)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *retentionPolicyResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentId == nil {
+		return nil, "", nil, nil
+	}
+
+	policies, err := o.client.GetRetentionPolicies(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list retention policies: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, policy := range policies {
+		policyCopy := policy
+		pr, err := retentionPolicyResource(&policyCopy, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, pr)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *retentionPolicyResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeEnterprise),
+		ent.WithDescription(fmt.Sprintf("Covered by %s Box retention policy", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s retention policy %s", resource.DisplayName, assigned)),
+	}
+
+	assignmentEn := ent.NewAssignmentEntitlement(resource, assigned, assignmentOptions...)
+	rv = append(rv, assignmentEn)
+
+	return rv, "", nil, nil
+}
+
+// Grants returns the enterprise assignments of the policy. Folders and metadata templates are not synced as resources,
+// so their assignments are only reflected in the assignment counts of the policy profile.
+func (o *retentionPolicyResourceType) Grants(ctx context.Context, resource *v2.Resource, token *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	assignments, err := o.client.GetRetentionPolicyAssignments(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list retention policy assignments: %w", err)
+	}
+
+	var rv []*v2.Grant
+	for _, assignment := range assignments {
+		if assignment.AssignedTo.Type != resourceTypeEnterprise.Id {
+			continue
+		}
+
+		enterpriseId, err := rs.NewResourceID(resourceTypeEnterprise, assignment.AssignedTo.ID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		assignmentGrant := grant.NewGrant(
+			resource,
+			assigned,
+			enterpriseId,
+			grant.WithGrantMetadata(map[string]interface{}{
+				"assigned_at":      assignment.AssignedAt,
+				"assigned_by":      assignment.AssignedBy.Login,
+				"start_date_field": assignment.StartDateField,
+			}),
+		)
+		rv = append(rv, assignmentGrant)
+	}
+
+	return rv, "", nil, nil
+}
+
+func retentionPolicyBuilder(client *box.Client) *retentionPolicyResourceType {
+	return &retentionPolicyResourceType{
+		resourceType: resourceTypeRetentionPolicy,
+		client:       client,
+	}
+}
@@ -16,6 +16,7 @@ import (
 type roleResourceType struct {
 	resourceType *v2.ResourceType
 	client       *box.Client
+	activity     *activityTracker
 }
 
 func (o *roleResourceType) ResourceType(_ context.Context) *v2.ResourceType {
@@ -91,6 +92,11 @@ func (o *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, to
 		return nil, "", nil, fmt.Errorf("box-connector: failed to list users: %w", err)
 	}
 
+	activity, err := o.activity.get(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to compute user activity: %w", err)
+	}
+
 	var rv []*v2.Grant
 	for _, user := range users {
 		userCopy := user
@@ -100,7 +106,15 @@ func (o *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, to
 		}
 
 		if resource.Id.Resource == user.Role {
-			permissionGrant := grant.NewGrant(resource, member, ur.Id)
+			var grantOptions []grant.GrantOption
+			// admin console sign ins are the only events that identify the use of a role.
+			if ua, ok := activity[user.ID]; ok && user.Role != roles["user"] && !ua.lastAdminLogin.IsZero() {
+				grantOptions = append(grantOptions, grant.WithGrantMetadata(map[string]interface{}{
+					"last_used_at": formatActivityTime(ua.lastAdminLogin),
+				}))
+			}
+
+			permissionGrant := grant.NewGrant(resource, member, ur.Id, grantOptions...)
 			rv = append(rv, permissionGrant)
 		}
 	}
@@ -108,9 +122,10 @@ func (o *roleResourceType) Grants(ctx context.Context, resource *v2.Resource, to
 	return rv, "", nil, nil
 }
 
-func roleBuilder(client *box.Client) *roleResourceType {
+func roleBuilder(client *box.Client, activity *activityTracker) *roleResourceType {
 	return &roleResourceType{
 		resourceType: resourceTypeRole,
 		client:       client,
+		activity:     activity,
 	}
 }
@@ -0,0 +1,78 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type shieldInformationBarrierResourceType struct {
+	resourceType *v2.ResourceType
+	client       *box.Client
+}
+
+func (o *shieldInformationBarrierResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Box Shield information barrier.
+func shieldInformationBarrierResource(barrier *box.ShieldInformationBarrier, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	// information barriers have no name, there is at most one barrier per enterprise.
+	ret, err := rs.NewResource(
+		fmt.Sprintf("Information barrier %s", barrier.ID),
+		resourceTypeShieldInformationBarrier,
+		barrier.ID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithAnnotation(
+			&v2.ChildResourceType{ResourceTypeId: resourceTypeShieldInformationBarrierSegment.Id},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *shieldInformationBarrierResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentId == nil {
+		return nil, "", nil, nil
+	}
+
+	barriers, err := o.client.GetShieldInformationBarriers(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list shield information barriers: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, barrier := range barriers {
+		barrierCopy := barrier
+		br, err := shieldInformationBarrierResource(&barrierCopy, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, br)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *shieldInformationBarrierResourceType) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func (o *shieldInformationBarrierResourceType) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func shieldInformationBarrierBuilder(client *box.Client) *shieldInformationBarrierResourceType {
+	return &shieldInformationBarrierResourceType{
+		resourceType: resourceTypeShieldInformationBarrier,
+		client:       client,
+	}
+}
@@ -0,0 +1,198 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	grant "github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+type shieldInformationBarrierSegmentResourceType struct {
+	resourceType *v2.ResourceType
+	client       *box.Client
+}
+
+func (o *shieldInformationBarrierSegmentResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Box Shield information barrier segment.
+func shieldInformationBarrierSegmentResource(
+	segment *box.ShieldInformationBarrierSegment,
+	restrictedSegments []string,
+	parentResourceID *v2.ResourceId,
+) (*v2.Resource, error) {
+	// restrictions are what makes a segment matter in an access review, so they are spelled out in the description.
+	description := segment.Description
+	if len(restrictedSegments) > 0 {
+		restricted := fmt.Sprintf("Cannot collaborate with %s", strings.Join(restrictedSegments, ", "))
+		if description == "" {
+			description = restricted
+		} else {
+			description = fmt.Sprintf("%s. %s", description, restricted)
+		}
+	}
+
+	ret, err := rs.NewResource(
+		segment.Name,
+		resourceTypeShieldInformationBarrierSegment,
+		segment.ID,
+		rs.WithDescription(description),
+		rs.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *shieldInformationBarrierSegmentResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentId == nil || parentId.ResourceType != resourceTypeShieldInformationBarrier.Id {
+		return nil, "", nil, nil
+	}
+
+	segments, err := o.client.GetShieldInformationBarrierSegments(ctx, parentId.Resource)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list shield information barrier segments: %w", err)
+	}
+
+	segmentNames := make(map[string]string, len(segments))
+	for _, segment := range segments {
+		segmentNames[segment.ID] = segment.Name
+	}
+
+	var rv []*v2.Resource
+	for _, segment := range segments {
+		segmentCopy := segment
+		restrictions, err := o.client.GetShieldInformationBarrierSegmentRestrictions(ctx, segment.ID)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("box-connector: failed to list shield information barrier segment restrictions: %w", err)
+		}
+
+		var restrictedSegments []string
+		for _, restriction := range restrictions {
+			name, ok := segmentNames[restriction.RestrictedSegment.ID]
+			if !ok {
+				name = restriction.RestrictedSegment.ID
+			}
+			restrictedSegments = append(restrictedSegments, name)
+		}
+
+		sr, err := shieldInformationBarrierSegmentResource(&segmentCopy, restrictedSegments, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, sr)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *shieldInformationBarrierSegmentResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := PopulateOptions(resource.DisplayName, member, resourceTypeShieldInformationBarrierSegment.DisplayName)
+	assignmentEn := ent.NewAssignmentEntitlement(resource, member, assignmentOptions...)
+	rv = append(rv, assignmentEn)
+
+	return rv, "", nil, nil
+}
+
+func (o *shieldInformationBarrierSegmentResourceType) Grants(ctx context.Context, resource *v2.Resource, token *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	members, err := o.client.GetShieldInformationBarrierSegmentMembers(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list shield information barrier segment members: %w", err)
+	}
+
+	var rv []*v2.Grant
+	for _, segmentMember := range members {
+		segmentMemberCopy := segmentMember
+		ur, err := userResource(&segmentMemberCopy.User, resource.Id)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		membershipGrant := grant.NewGrant(resource, member, ur.Id)
+		rv = append(rv, membershipGrant)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *shieldInformationBarrierSegmentResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		l.Warn(
+			"box-connector: only users can be added to a shield information barrier segment",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("box-connector: only users can be added to a shield information barrier segment")
+	}
+
+	err := o.client.AddShieldInformationBarrierSegmentMember(ctx, entitlement.Resource.Id.Resource, principal.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("box-connector: failed to add shield information barrier segment member: %w", err)
+	}
+
+	return nil, nil
+}
+
+func (o *shieldInformationBarrierSegmentResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	entitlement := grant.Entitlement
+
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		l.Warn(
+			"box-connector: only users can be removed from a shield information barrier segment",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("box-connector: only users can be removed from a shield information barrier segment")
+	}
+
+	members, err := o.client.GetShieldInformationBarrierSegmentMembers(ctx, entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("box-connector: failed to list shield information barrier segment members: %w", err)
+	}
+
+	for _, segmentMember := range members {
+		if segmentMember.User.ID != principal.Id.Resource {
+			continue
+		}
+
+		err := o.client.RemoveShieldInformationBarrierSegmentMember(ctx, segmentMember.ID)
+		if err != nil {
+			return nil, fmt.Errorf("box-connector: failed to remove shield information barrier segment member: %w", err)
+		}
+		return nil, nil
+	}
+
+	l.Info(
+		"box-connector: user is not a member of the shield information barrier segment",
+		zap.String("segment_id", entitlement.Resource.Id.Resource),
+		zap.String("user_id", principal.Id.Resource),
+	)
+
+	return nil, nil
+}
+
+func shieldInformationBarrierSegmentBuilder(client *box.Client) *shieldInformationBarrierSegmentResourceType {
+	return &shieldInformationBarrierSegmentResourceType{
+		resourceType: resourceTypeShieldInformationBarrierSegment,
+		client:       client,
+	}
+}
@@ -0,0 +1,180 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	grant "github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+type storagePolicyResourceType struct {
+	resourceType *v2.ResourceType
+	client       *box.Client
+	assignments  *storagePolicyAssignments
+}
+
+func (o *storagePolicyResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Box storage policy.
+func storagePolicyResource(policy *box.StoragePolicy, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ret, err := rs.NewResource(
+		policy.Name,
+		resourceTypeStoragePolicy,
+		policy.ID,
+		rs.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *storagePolicyResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentId == nil {
+		return nil, "", nil, nil
+	}
+
+	policies, err := o.client.GetStoragePolicies(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list storage policies: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, policy := range policies {
+		policyCopy := policy
+		pr, err := storagePolicyResource(&policyCopy, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, pr)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *storagePolicyResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	assignmentOptions := []ent.EntitlementOption{
+		ent.WithGrantableTo(resourceTypeUser),
+		ent.WithDescription(fmt.Sprintf("Stores data according to %s Box storage policy", resource.DisplayName)),
+		ent.WithDisplayName(fmt.Sprintf("%s storage policy %s", resource.DisplayName, assigned)),
+	}
+
+	assignmentEn := ent.NewAssignmentEntitlement(resource, assigned, assignmentOptions...)
+	rv = append(rv, assignmentEn)
+
+	return rv, "", nil, nil
+}
+
+// Grants returns the users whose effective storage policy is the policy.
+func (o *storagePolicyResourceType) Grants(ctx context.Context, resource *v2.Resource, token *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	byPolicy, err := o.assignments.get(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list storage policy assignments: %w", err)
+	}
+
+	var rv []*v2.Grant
+	for _, policyUser := range byPolicy[resource.Id.Resource] {
+		userCopy := policyUser.user
+		ur, err := userResource(&userCopy, resource.Id)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		assignmentGrant := grant.NewGrant(
+			resource,
+			assigned,
+			ur.Id,
+			grant.WithGrantMetadata(map[string]interface{}{
+				"inherited_from_enterprise": policyUser.assignment.AssignedTo.Type == resourceTypeEnterprise.Id,
+			}),
+		)
+		rv = append(rv, assignmentGrant)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *storagePolicyResourceType) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		l.Warn(
+			"box-connector: only users can be assigned a storage policy",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("box-connector: only users can be assigned a storage policy")
+	}
+
+	err := o.client.AssignStoragePolicy(ctx, entitlement.Resource.Id.Resource, principal.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("box-connector: failed to assign storage policy: %w", err)
+	}
+	o.assignments.invalidate()
+
+	return nil, nil
+}
+
+// Revoke removes the user's own storage policy assignment, the user falls back to the enterprise storage policy.
+func (o *storagePolicyResourceType) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	l := ctxzap.Extract(ctx)
+
+	principal := grant.Principal
+	entitlement := grant.Entitlement
+
+	if principal.Id.ResourceType != resourceTypeUser.Id {
+		l.Warn(
+			"box-connector: only users can have a storage policy revoked",
+			zap.String("principal_type", principal.Id.ResourceType),
+			zap.String("principal_id", principal.Id.Resource),
+		)
+		return nil, fmt.Errorf("box-connector: only users can have a storage policy revoked")
+	}
+
+	assignment, err := o.client.GetUserStoragePolicyAssignment(ctx, principal.Id.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("box-connector: failed to get storage policy assignment: %w", err)
+	}
+
+	if assignment.StoragePolicy.ID != entitlement.Resource.Id.Resource {
+		l.Info(
+			"box-connector: user is not assigned the storage policy",
+			zap.String("storage_policy_id", entitlement.Resource.Id.Resource),
+			zap.String("user_id", principal.Id.Resource),
+		)
+		return nil, nil
+	}
+
+	if assignment.AssignedTo.Type != resourceTypeUser.Id {
+		return nil, fmt.Errorf("box-connector: storage policy is inherited from the enterprise and cannot be revoked for a single user")
+	}
+
+	err = o.client.DeleteStoragePolicyAssignment(ctx, assignment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("box-connector: failed to delete storage policy assignment: %w", err)
+	}
+	o.assignments.invalidate()
+
+	return nil, nil
+}
+
+func storagePolicyBuilder(client *box.Client, assignments *storagePolicyAssignments) *storagePolicyResourceType {
+	return &storagePolicyResourceType{
+		resourceType: resourceTypeStoragePolicy,
+		client:       client,
+		assignments:  assignments,
+	}
+}
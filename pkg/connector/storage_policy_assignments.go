@@ -0,0 +1,67 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-box/pkg/box"
+)
+
+// storagePolicyUser is a user together with the storage policy assignment that applies to them.
+type storagePolicyUser struct {
+	user       box.User
+	assignment box.StoragePolicyAssignment
+}
+
+// storagePolicyAssignments resolves the effective storage policy of every user once and keeps the users per policy.
+type storagePolicyAssignments struct {
+	client *box.Client
+	users  *enterpriseUsers
+
+	byPolicy ttlCache[map[string][]storagePolicyUser]
+}
+
+func newStoragePolicyAssignments(client *box.Client, users *enterpriseUsers) *storagePolicyAssignments {
+	return &storagePolicyAssignments{
+		client: client,
+		users:  users,
+	}
+}
+
+// get returns the users keyed by the ID of their effective storage policy.
+func (s *storagePolicyAssignments) get(ctx context.Context) (map[string][]storagePolicyUser, error) {
+	return s.byPolicy.get(ctx, s.fetch)
+}
+
+func (s *storagePolicyAssignments) fetch(ctx context.Context) (map[string][]storagePolicyUser, error) {
+	users, err := s.users.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Box can only resolve assignments per user.
+	assignments := make([]box.StoragePolicyAssignment, len(users))
+	err = forEachConcurrently(ctx, len(users), perUserRequestConcurrency, func(ctx context.Context, i int) error {
+		assignment, err := s.client.GetUserStoragePolicyAssignment(ctx, users[i].ID)
+		if err != nil {
+			return err
+		}
+		assignments[i] = assignment
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byPolicy := make(map[string][]storagePolicyUser)
+	for i, user := range users {
+		policyID := assignments[i].StoragePolicy.ID
+		byPolicy[policyID] = append(byPolicy[policyID], storagePolicyUser{user: user, assignment: assignments[i]})
+	}
+
+	return byPolicy, nil
+}
+
+// invalidate drops the cached assignments after they were changed through provisioning.
+func (s *storagePolicyAssignments) invalidate() {
+	s.byPolicy.invalidate()
+}
@@ -0,0 +1,124 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-box/pkg/box"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	ent "github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	grant "github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const (
+	accepted = "accepted"
+	rejected = "rejected"
+)
+
+type termsOfServiceResourceType struct {
+	resourceType *v2.ResourceType
+	client       *box.Client
+}
+
+func (o *termsOfServiceResourceType) ResourceType(_ context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// Create a new connector resource for a Box terms of service.
+func termsOfServiceResource(tos *box.TermsOfService, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	// terms of service have no name, an enterprise has at most one of each type.
+	ret, err := rs.NewResource(
+		fmt.Sprintf("%s terms of service", titleCase(tos.TosType)),
+		resourceTypeTermsOfService,
+		tos.ID,
+		rs.WithParentResourceID(parentResourceID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (o *termsOfServiceResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentId == nil {
+		return nil, "", nil, nil
+	}
+
+	termsOfServices, err := o.client.GetTermsOfServices(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list terms of services: %w", err)
+	}
+
+	var rv []*v2.Resource
+	for _, tos := range termsOfServices {
+		tosCopy := tos
+		tr, err := termsOfServiceResource(&tosCopy, parentId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, tr)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *termsOfServiceResourceType) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	for _, state := range []string{accepted, rejected} {
+		options := []ent.EntitlementOption{
+			ent.WithGrantableTo(resourceTypeUser),
+			ent.WithDescription(fmt.Sprintf("%s %s in Box", titleCase(state), resource.DisplayName)),
+			ent.WithDisplayName(fmt.Sprintf("%s %s", resource.DisplayName, state)),
+		}
+		rv = append(rv, ent.NewAssignmentEntitlement(resource, state, options...))
+	}
+
+	return rv, "", nil, nil
+}
+
+func (o *termsOfServiceResourceType) Grants(ctx context.Context, resource *v2.Resource, token *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	userStatuses, err := o.client.GetTermsOfServiceUserStatuses(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list terms of service user statuses: %w", err)
+	}
+
+	var rv []*v2.Grant
+	for _, userStatus := range userStatuses {
+		userStatusCopy := userStatus
+		ur, err := userResource(&userStatusCopy.User, resource.Id)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		state := rejected
+		if userStatus.IsAccepted {
+			state = accepted
+		}
+
+		statusGrant := grant.NewGrant(
+			resource,
+			state,
+			ur.Id,
+			grant.WithGrantMetadata(map[string]interface{}{
+				"is_accepted": userStatus.IsAccepted,
+				"created_at":  userStatus.CreatedAt,
+				"modified_at": userStatus.ModifiedAt,
+			}),
+		)
+		rv = append(rv, statusGrant)
+	}
+
+	return rv, "", nil, nil
+}
+
+func termsOfServiceBuilder(client *box.Client) *termsOfServiceResourceType {
+	return &termsOfServiceResourceType{
+		resourceType: resourceTypeTermsOfService,
+		client:       client,
+	}
+}
@@ -10,11 +10,19 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	// appUserDomain is the login domain Box assigns to App Users created for platform apps.
+	appUserDomain = "@boxdevedition.com"
 )
 
 type userResourceType struct {
 	resourceType *v2.ResourceType
 	client       *box.Client
+	activity     *activityTracker
 }
 
 func (o *userResourceType) ResourceType(_ context.Context) *v2.ResourceType {
@@ -34,26 +42,93 @@ func userResource(user *box.User, parentResourceID *v2.ResourceId) (*v2.Resource
 	}
 
 	profile := map[string]interface{}{
-		"first_name": firstName,
-		"last_name":  lastName,
-		"login":      user.Login,
-		"user_id":    user.ID,
+		"first_name":                        firstName,
+		"last_name":                         lastName,
+		"login":                             user.Login,
+		"user_id":                           user.ID,
+		"job_title":                         user.JobTitle,
+		"phone":                             user.Phone,
+		"address":                           user.Address,
+		"language":                          user.Language,
+		"timezone":                          user.Timezone,
+		"created_at":                        user.CreatedAt,
+		"modified_at":                       user.ModifiedAt,
+		"space_amount":                      user.SpaceAmount,
+		"space_used":                        user.SpaceUsed,
+		"max_upload_size":                   user.MaxUploadSize,
+		"is_sync_enabled":                   user.IsSyncEnabled,
+		"is_external_collab_restricted":     user.IsExternalCollabRestricted,
+		"is_exempt_from_device_limits":      user.IsExemptFromDeviceLimits,
+		"is_exempt_from_login_verification": user.IsExemptFromLoginVerification,
+		"is_platform_access_only":           user.IsPlatformAccessOnly,
+		"notification_email":                user.NotificationEmail.Email,
+	}
+
+	if len(user.TrackingCodes) > 0 {
+		trackingCodes := make(map[string]interface{}, len(user.TrackingCodes))
+		for _, trackingCode := range user.TrackingCodes {
+			trackingCodes[trackingCode.Name] = trackingCode.Value
+		}
+		profile["tracking_codes"] = trackingCodes
+	}
+
+	var unconfirmedAliases []interface{}
+	for _, alias := range user.EmailAliases {
+		if !alias.IsConfirmed {
+			unconfirmedAliases = append(unconfirmedAliases, alias.Email)
+		}
+	}
+	if len(unconfirmedAliases) > 0 {
+		profile["unconfirmed_email_aliases"] = unconfirmedAliases
 	}
 
+	if user.LastLoginAt != "" {
+		profile["last_login_at"] = user.LastLoginAt
+	}
+	if user.LastActivityAt != "" {
+		profile["last_activity_at"] = user.LastActivityAt
+	}
+
+	// extra fields never override the fields mapped above.
+	for field, value := range user.ExtraFields {
+		if _, ok := profile[field]; !ok {
+			profile[field] = value
+		}
+	}
+
+	profile["status"] = user.Status
+
 	var status v2.UserTrait_Status_Status
 	switch user.Status {
-	case "active":
+	case box.UserStatusActive:
 		status = v2.UserTrait_Status_STATUS_ENABLED
-	case "inactive":
+	case box.UserStatusCannotDeleteEdit, box.UserStatusCannotDeleteEditUpload:
+		// restricted users can still sign in, they just cannot change or upload content.
+		status = v2.UserTrait_Status_STATUS_ENABLED
+		profile["restricted"] = true
+	case box.UserStatusInactive:
 		status = v2.UserTrait_Status_STATUS_DISABLED
 	default:
+		// pending and invited users have no matching status in Baton, the raw status is kept in the profile.
 		status = v2.UserTrait_Status_STATUS_UNSPECIFIED
 	}
 
+	accountType := v2.UserTrait_ACCOUNT_TYPE_HUMAN
+	if isServiceAccount(user) {
+		accountType = v2.UserTrait_ACCOUNT_TYPE_SERVICE
+	}
+
 	userTraitOptions := []rs.UserTraitOption{
 		rs.WithUserProfile(profile),
 		rs.WithEmail(user.Login, true),
 		rs.WithStatus(status),
+		rs.WithAccountType(accountType),
+	}
+
+	for _, alias := range user.EmailAliases {
+		if alias.IsConfirmed {
+			userTraitOptions = append(userTraitOptions, rs.WithEmail(alias.Email, false))
+		}
 	}
 
 	ret, err := rs.NewUserResource(
@@ -70,6 +145,11 @@ func userResource(user *box.User, parentResourceID *v2.ResourceId) (*v2.Resource
 	return ret, nil
 }
 
+// isServiceAccount reports whether the Box user is a machine identity rather than a person.
+func isServiceAccount(user *box.User) bool {
+	return user.IsPlatformAccessOnly || strings.HasSuffix(strings.ToLower(user.Login), appUserDomain)
+}
+
 func (o *userResourceType) List(ctx context.Context, parentId *v2.ResourceId, token *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	if parentId == nil {
 		return nil, "", nil, nil
@@ -80,6 +160,41 @@ func (o *userResourceType) List(ctx context.Context, parentId *v2.ResourceId, to
 		return nil, "", nil, fmt.Errorf("box-connector: failed to list users: %w", err)
 	}
 
+	l := ctxzap.Extract(ctx)
+	err = forEachConcurrently(ctx, len(users), perUserRequestConcurrency, func(ctx context.Context, i int) error {
+		aliases, err := o.client.GetEmailAliases(ctx, users[i].ID)
+		if err != nil {
+			// a single user's aliases are not worth failing the page, the user is synced without them.
+			l.Warn(
+				"box-connector: failed to list email aliases, syncing user without them",
+				zap.String("user_id", users[i].ID),
+				zap.Error(err),
+			)
+			return nil
+		}
+		users[i].EmailAliases = aliases
+		return nil
+	})
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to list email aliases: %w", err)
+	}
+
+	activity, err := o.activity.get(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("box-connector: failed to compute user activity: %w", err)
+	}
+
+	for i := range users {
+		ua, ok := activity[users[i].ID]
+		if !ok {
+			continue
+		}
+		if !ua.lastLogin.IsZero() {
+			users[i].LastLoginAt = formatActivityTime(ua.lastLogin)
+		}
+		users[i].LastActivityAt = formatActivityTime(ua.lastActivity)
+	}
+
 	var rv []*v2.Resource
 	for _, baseUser := range users {
 		baseUserCopy := baseUser
@@ -101,9 +216,10 @@ func (o *userResourceType) Grants(_ context.Context, _ *v2.Resource, _ *paginati
 	return nil, "", nil, nil
 }
 
-func userBuilder(client *box.Client) *userResourceType {
+func userBuilder(client *box.Client, activity *activityTracker) *userResourceType {
 	return &userResourceType{
 		resourceType: resourceTypeUser,
 		client:       client,
+		activity:     activity,
 	}
 }